@@ -13,13 +13,14 @@ import (
 
 func init() {
 	injection.Register[observability.Logger](func(ctx context.Context) observability.Logger {
-		return logger{}
+		return logger{minLevel: observability.MinLevel(ctx)}
 	})
 }
 
 type logger struct {
 	fields      map[string]string
 	builtFields string
+	minLevel    observability.Level
 }
 
 func (l logger) WithField(name, value string) observability.Logger {
@@ -34,7 +35,8 @@ func (l logger) WithField(name, value string) observability.Logger {
 	m[name] = value
 
 	log := logger{
-		fields: m,
+		fields:   m,
+		minLevel: l.minLevel,
 	}
 	log.builtFields = log.buildFields()
 
@@ -113,23 +115,40 @@ const (
 	red    = "\033[31m"
 	green  = "\033[32m"
 	yellow = "\033[33m"
+	cyan   = "\033[36m"
 	reset  = "\033[0m"
 )
 
-// Fatalf implements Logger.
-func (l logger) Fatalf(format string, args ...any) {
-	s := fmt.Sprintf(red+"[FATAL] "+reset+format, args...)
-	log.Fatal(s + "\n" + l.builtFields)
+// Debugf implements Logger.
+func (l logger) Debugf(format string, args ...any) {
+	l.logf(observability.LevelDebug, cyan, format, args...)
+}
+
+// Infof implements Logger.
+func (l logger) Infof(format string, args ...any) {
+	l.logf(observability.LevelInfo, green, format, args...)
 }
 
 // Warnf implements Logger.
 func (l logger) Warnf(format string, args ...any) {
-	s := fmt.Sprintf(red+"[WARN] "+reset+format, args...)
-	log.Print(s + "\n" + l.builtFields)
+	l.logf(observability.LevelWarn, yellow, format, args...)
 }
 
-// Infof implements Logger.
-func (l logger) Infof(format string, args ...any) {
-	s := fmt.Sprintf(green+"[INFO] "+reset+format, args...)
+// Errorf implements Logger.
+func (l logger) Errorf(format string, args ...any) {
+	l.logf(observability.LevelError, red, format, args...)
+}
+
+// Fatalf implements Logger. It is written regardless of the minimum level.
+func (l logger) Fatalf(format string, args ...any) {
+	s := fmt.Sprintf(red+"[FATAL] "+reset+format, args...)
+	log.Fatal(s + "\n" + l.builtFields)
+}
+
+func (l logger) logf(level observability.Level, color, format string, args ...any) {
+	if level < l.minLevel {
+		return
+	}
+	s := fmt.Sprintf(color+"["+level.String()+"] "+reset+format, args...)
 	log.Print(s + "\n" + l.builtFields)
 }
@@ -18,6 +18,7 @@ const (
 	red    = "\033[31m"
 	green  = "\033[32m"
 	yellow = "\033[33m"
+	cyan   = "\033[36m"
 	reset  = "\033[0m"
 )
 
@@ -43,7 +44,7 @@ func TestLogger_WithoutFields(t *testing.T) {
 		t.Errorf("got %s, want %s", actual, expected)
 	}
 	scanner.Scan()
-	if actual, expected := scanner.Text(), fmt.Sprintf("%s[WARN] %swarn world", red, reset); actual != expected {
+	if actual, expected := scanner.Text(), fmt.Sprintf("%s[WARN] %swarn world", yellow, reset); actual != expected {
 		t.Errorf("got %s, want %s", actual, expected)
 	}
 }
@@ -67,7 +68,7 @@ func TestLogger_WithFields_NewInstance(t *testing.T) {
 		t.Errorf("got %s, want %s", actual, expected)
 	}
 	scanner.Scan()
-	if actual, expected := scanner.Text(), fmt.Sprintf("%s[WARN] %swarn world", red, reset); actual != expected {
+	if actual, expected := scanner.Text(), fmt.Sprintf("%s[WARN] %swarn world", yellow, reset); actual != expected {
 		t.Errorf("got %s, want %s", actual, expected)
 	}
 }
@@ -225,3 +226,45 @@ func replaceWhiteSpace(s string) string {
 	s = strings.ReplaceAll(s, "\n", "-")
 	return s
 }
+
+func TestLogger_Levels(t *testing.T) {
+	testOutput.Reset()
+
+	ctx := injectiontesting.WithTesting(t)
+	log := injection.Resolve[observability.Logger](ctx)
+	log.Debugf("debug %s", "world")
+	log.Errorf("error %s", "world")
+
+	scanner := bufio.NewScanner(&testOutput)
+	scanner.Scan()
+	if actual, expected := scanner.Text(), fmt.Sprintf("%s[ERROR] %serror world", red, reset); actual != expected {
+		t.Errorf("got %s, want %s", actual, expected)
+	}
+}
+
+func TestLogger_MinLevelFromEnv(t *testing.T) {
+	testOutput.Reset()
+	t.Setenv(observability.LevelEnvVar, "debug")
+
+	ctx := injectiontesting.WithTesting(t)
+	log := injection.Resolve[observability.Logger](ctx)
+	log.Debugf("debug %s", "world")
+
+	scanner := bufio.NewScanner(&testOutput)
+	scanner.Scan()
+	if actual, expected := scanner.Text(), fmt.Sprintf("%s[DEBUG] %sdebug world", cyan, reset); actual != expected {
+		t.Errorf("got %s, want %s", actual, expected)
+	}
+
+	testOutput.Reset()
+	t.Setenv(observability.LevelEnvVar, "error")
+
+	ctx = injectiontesting.WithTesting(t)
+	log = injection.Resolve[observability.Logger](ctx)
+	log.WithField("key", "value").Infof("info %s", "world")
+	log.Warnf("warn %s", "world")
+
+	if actual := testOutput.String(); actual != "" {
+		t.Errorf("expected no output, got %s", replaceWhiteSpace(actual))
+	}
+}
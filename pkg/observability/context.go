@@ -0,0 +1,41 @@
+package observability
+
+import (
+	"context"
+	"log"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns a new context with the logger set.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger from the context. The router sets a
+// request-scoped logger for each request. Otherwise, the injected Logger is
+// returned if ctx is from the injection system, falling back to a logger
+// that uses the standard library's log package.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+		return l
+	}
+	if l, ok := injection.TryResolve[Logger](ctx); ok && l != nil {
+		return l
+	}
+	return stdLogger{}
+}
+
+// stdLogger is a Logger that uses the standard library's logger. Fields are
+// dropped.
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...any) { log.Printf("[DEBUG] "+format, args...) }
+func (stdLogger) Infof(format string, args ...any)  { log.Printf("[INFO] "+format, args...) }
+func (stdLogger) Warnf(format string, args ...any)  { log.Printf("[WARN] "+format, args...) }
+func (stdLogger) Errorf(format string, args ...any) { log.Printf("[ERROR] "+format, args...) }
+func (stdLogger) Fatalf(format string, args ...any) { log.Fatalf("[FATAL] "+format, args...) }
+
+func (l stdLogger) WithField(name, value string) Logger { return l }
@@ -0,0 +1,100 @@
+package observability_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/observability"
+)
+
+type namedLogger struct {
+	observability.Logger
+	name string
+}
+
+func init() {
+	injection.Register[observability.Logger](func(ctx context.Context) observability.Logger {
+		return namedLogger{name: "injected"}
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := observability.WithLogger(context.Background(), namedLogger{name: "request"})
+	if l, ok := observability.FromContext(ctx).(namedLogger); !ok || l.name != "request" {
+		t.Fatalf("expected the request logger, got %#v", l)
+	}
+}
+
+func TestFromContext_injected(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	if l, ok := observability.FromContext(ctx).(namedLogger); !ok || l.name != "injected" {
+		t.Fatalf("expected the injected logger, got %#v", l)
+	}
+}
+
+func TestFromContext_fallback(t *testing.T) {
+	t.Parallel()
+
+	l := observability.FromContext(context.Background())
+	if l == nil {
+		t.Fatal("expected a logger")
+	}
+	if _, ok := l.(namedLogger); ok {
+		t.Fatal("expected the fallback logger")
+	}
+
+	// The fallback must be usable.
+	l.WithField("some", "field").Infof("hello")
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for s, expected := range map[string]observability.Level{
+		"debug":   observability.LevelDebug,
+		"INFO":    observability.LevelInfo,
+		"warning": observability.LevelWarn,
+		" error ": observability.LevelError,
+		"fatal":   observability.LevelFatal,
+	} {
+		actual, err := observability.ParseLevel(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("%q: expected %v, got %v", s, expected, actual)
+		}
+	}
+
+	if _, err := observability.ParseLevel("loud"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestMinLevel(t *testing.T) {
+	t.Setenv(observability.LevelEnvVar, "")
+	if actual, expected := observability.MinLevel(context.Background()), observability.LevelInfo; actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	t.Setenv(observability.LevelEnvVar, "debug")
+	if actual, expected := observability.MinLevel(context.Background()), observability.LevelDebug; actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestMinLevel_injected(t *testing.T) {
+	t.Setenv(observability.LevelEnvVar, "")
+	observability.AddMinLevel(observability.LevelWarn)
+
+	ctx := injectiontesting.WithTesting(t)
+	if actual, expected := observability.MinLevel(ctx), observability.LevelWarn; actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
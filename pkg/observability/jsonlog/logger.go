@@ -0,0 +1,176 @@
+// Package jsonlog provides an observability.Logger that writes one JSON
+// object per line. It is registered with the injection system when the
+// package is imported:
+//
+//	import _ "github.com/poy/go-router/pkg/observability/jsonlog"
+package jsonlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/observability"
+)
+
+func init() {
+	injection.Register[observability.Logger](func(ctx context.Context) observability.Logger {
+		var w io.Writer = os.Stderr
+		if o, ok := injection.TryResolve[output](ctx); ok {
+			w = o.Writer
+		}
+		return New(w, observability.MinLevel(ctx))
+	})
+}
+
+type output struct {
+	io.Writer
+}
+
+// AddOutput sets where the registered logger writes to. It defaults to
+// os.Stderr.
+func AddOutput(w io.Writer) {
+	injection.Register[output](func(ctx context.Context) output {
+		return output{Writer: w}
+	})
+}
+
+// New returns a logger that writes entries with at least the given level
+// to w. Each entry is a JSON object on its own line with the time, level and
+// message keys, followed by every field as a top-level key. Fields named
+// like one of those keys are dropped.
+func New(w io.Writer, minLevel observability.Level) observability.Logger {
+	return logger{
+		out: &syncWriter{w: w},
+		min: minLevel,
+	}
+}
+
+// syncWriter serializes the writes of a logger and every logger derived from
+// it via WithField.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) write(data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.w.Write(data)
+}
+
+type logger struct {
+	out    *syncWriter
+	min    observability.Level
+	fields map[string]string
+}
+
+// WithField implements Logger.
+func (l logger) WithField(name, value string) observability.Logger {
+	m := make(map[string]string, len(l.fields)+1)
+	for k, v := range l.fields {
+		m[k] = v
+	}
+	m[name] = value
+
+	l.fields = m
+	return l
+}
+
+// Debugf implements Logger.
+func (l logger) Debugf(format string, args ...any) {
+	l.logf(observability.LevelDebug, format, args...)
+}
+
+// Infof implements Logger.
+func (l logger) Infof(format string, args ...any) {
+	l.logf(observability.LevelInfo, format, args...)
+}
+
+// Warnf implements Logger.
+func (l logger) Warnf(format string, args ...any) {
+	l.logf(observability.LevelWarn, format, args...)
+}
+
+// Errorf implements Logger.
+func (l logger) Errorf(format string, args ...any) {
+	l.logf(observability.LevelError, format, args...)
+}
+
+// Fatalf implements Logger. It is written regardless of the minimum level
+// and then exits.
+func (l logger) Fatalf(format string, args ...any) {
+	l.logf(observability.LevelFatal, format, args...)
+	os.Exit(1)
+}
+
+var reservedKeys = map[string]bool{
+	"time":    true,
+	"level":   true,
+	"message": true,
+}
+
+func (l logger) logf(level observability.Level, format string, args ...any) {
+	if level < l.min && level != observability.LevelFatal {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeKeyValue(&buf, "time", time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeKeyValue(&buf, "level", levelName(level))
+	buf.WriteByte(',')
+	writeKeyValue(&buf, "message", fmt.Sprintf(format, args...))
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		if !reservedKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.WriteByte(',')
+		writeKeyValue(&buf, k, l.fields[k])
+	}
+	buf.WriteString("}\n")
+
+	l.out.write(buf.Bytes())
+}
+
+func writeKeyValue(buf *bytes.Buffer, key, value string) {
+	writeString(buf, key)
+	buf.WriteByte(':')
+	writeString(buf, value)
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	// Encoding a string can't fail.
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+func levelName(l observability.Level) string {
+	switch l {
+	case observability.LevelDebug:
+		return "debug"
+	case observability.LevelInfo:
+		return "info"
+	case observability.LevelWarn:
+		return "warn"
+	case observability.LevelError:
+		return "error"
+	case observability.LevelFatal:
+		return "fatal"
+	default:
+		return l.String()
+	}
+}
@@ -0,0 +1,93 @@
+package jsonlog_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/observability"
+	"github.com/poy/go-router/pkg/observability/jsonlog"
+)
+
+func decodeLines(t *testing.T, data []byte) []map[string]string {
+	t.Helper()
+
+	var entries []map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var m map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, m)
+	}
+	return entries
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	log := jsonlog.New(&buf, observability.LevelInfo)
+	log.Debugf("dropped")
+	log.WithField("key", "value").WithField("level", "ignored").Infof("hello %s", "world")
+	log.Warnf("warn \"quoted\"\nnewline")
+
+	entries := decodeLines(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", entries)
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, entries[0]["time"]); err != nil {
+		t.Fatalf("invalid time: %v", err)
+	}
+	delete(entries[0], "time")
+	if expected := map[string]string{
+		"level":   "info",
+		"message": "hello world",
+		"key":     "value",
+	}; !reflect.DeepEqual(entries[0], expected) {
+		t.Fatalf("expected %v, got %v", expected, entries[0])
+	}
+
+	if actual, expected := entries[1]["message"], "warn \"quoted\"\nnewline"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	if _, ok := entries[1]["key"]; ok {
+		t.Fatal("expected the field to only be on the derived logger")
+	}
+}
+
+func TestLogger_keyOrder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	jsonlog.New(&buf, observability.LevelDebug).WithField("b", "2").WithField("a", "1").Debugf("hello")
+
+	line := buf.String()
+	if !strings.HasPrefix(line, `{"time":`) || !strings.HasSuffix(line, `,"level":"debug","message":"hello","a":"1","b":"2"}`+"\n") {
+		t.Fatalf("unexpected line: %s", line)
+	}
+}
+
+func TestLogger_injection(t *testing.T) {
+	var buf bytes.Buffer
+	jsonlog.AddOutput(&buf)
+	t.Setenv(observability.LevelEnvVar, "error")
+
+	ctx := injectiontesting.WithTesting(t)
+	log := injection.Resolve[observability.Logger](ctx)
+	log.Warnf("dropped")
+	log.Errorf("failed")
+
+	entries := decodeLines(t, buf.Bytes())
+	if len(entries) != 1 || entries[0]["message"] != "failed" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+}
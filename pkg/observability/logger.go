@@ -1,15 +1,99 @@
 package observability
 
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
 // Logger is the interface that provides a logging abstraction.
 type Logger interface {
-	// TODO: Eventually, we should actually decide on a logger.
-	// Debugf(format string, args ...any)
-	// Errorf(format string, args ...any)
-
-	Fatalf(format string, args ...any)
-	Warnf(format string, args ...any)
+	Debugf(format string, args ...any)
 	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
 
 	// WithField returns a new logger with the given field.
 	WithField(name, value string) Logger
 }
+
+// Level is the severity of a log entry.
+type Level int
+
+// The levels in increasing severity.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// String implements fmt.Stringer.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "FATAL"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name (e.g., debug or WARN). "warning" is
+// accepted for LevelWarn.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+// LevelEnvVar is the environment variable that sets the minimum level of
+// the loggers (see MinLevel).
+const LevelEnvVar = "LOG_LEVEL"
+
+// AddMinLevel sets the minimum level that the loggers write. Entries with a
+// lower level are dropped. The LOG_LEVEL environment variable takes
+// precedence.
+func AddMinLevel(l Level) {
+	injection.Register[Level](func(ctx context.Context) Level {
+		return l
+	})
+}
+
+// MinLevel returns the minimum level that a Logger implementation should
+// write. It is read from the LOG_LEVEL environment variable, then the level
+// registered with AddMinLevel and defaults to LevelInfo.
+func MinLevel(ctx context.Context) Level {
+	if s, ok := os.LookupEnv(LevelEnvVar); ok && s != "" {
+		if l, err := ParseLevel(s); err == nil {
+			return l
+		}
+	}
+	if l, ok := injection.TryResolve[Level](ctx); ok {
+		return l
+	}
+	return LevelInfo
+}
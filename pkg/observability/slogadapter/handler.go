@@ -0,0 +1,84 @@
+package slogadapter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/poy/go-router/pkg/observability"
+)
+
+// NewHandler returns a slog.Handler that forwards each record to l. The
+// record's attributes are added as fields (see Logger.WithField). Attributes
+// within a group have the group's name as a prefix (e.g., request.method).
+// The level of the record decides the Logger's method: Debugf below
+// slog.LevelInfo, Infof below slog.LevelWarn, Warnf below slog.LevelError
+// and Errorf otherwise. Filtering by level is left to l.
+func NewHandler(l observability.Logger) slog.Handler {
+	return handler{l: l}
+}
+
+type handler struct {
+	l      observability.Logger
+	prefix string
+}
+
+// Enabled implements slog.Handler.
+func (h handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler.
+func (h handler) Handle(_ context.Context, r slog.Record) error {
+	l := h.l
+	r.Attrs(func(a slog.Attr) bool {
+		l = withAttr(l, h.prefix, a)
+		return true
+	})
+
+	switch {
+	case r.Level < slog.LevelInfo:
+		l.Debugf("%s", r.Message)
+	case r.Level < slog.LevelWarn:
+		l.Infof("%s", r.Message)
+	case r.Level < slog.LevelError:
+		l.Warnf("%s", r.Message)
+	default:
+		l.Errorf("%s", r.Message)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for _, a := range attrs {
+		h.l = withAttr(h.l, h.prefix, a)
+	}
+	return h
+}
+
+// WithGroup implements slog.Handler.
+func (h handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h.prefix += name + "."
+	return h
+}
+
+func withAttr(l observability.Logger, prefix string, a slog.Attr) observability.Logger {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return l
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			l = withAttr(l, prefix, ga)
+		}
+		return l
+	}
+	return l.WithField(prefix+a.Key, a.Value.String())
+}
@@ -0,0 +1,71 @@
+package slogadapter_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/poy/go-router/pkg/observability"
+	"github.com/poy/go-router/pkg/observability/slogadapter"
+)
+
+type entry struct {
+	level   string
+	message string
+	fields  map[string]string
+}
+
+// recordingLogger is an observability.Logger that records each entry.
+type recordingLogger struct {
+	entries *[]entry
+	fields  map[string]string
+}
+
+func (l recordingLogger) record(level, format string, args ...any) {
+	*l.entries = append(*l.entries, entry{level: level, message: fmt.Sprintf(format, args...), fields: l.fields})
+}
+
+func (l recordingLogger) Debugf(format string, args ...any) { l.record("debug", format, args...) }
+func (l recordingLogger) Infof(format string, args ...any)  { l.record("info", format, args...) }
+func (l recordingLogger) Warnf(format string, args ...any)  { l.record("warn", format, args...) }
+func (l recordingLogger) Errorf(format string, args ...any) { l.record("error", format, args...) }
+func (l recordingLogger) Fatalf(format string, args ...any) { l.record("fatal", format, args...) }
+
+func (l recordingLogger) WithField(name, value string) observability.Logger {
+	fields := map[string]string{name: value}
+	for k, v := range l.fields {
+		if k != name {
+			fields[k] = v
+		}
+	}
+	l.fields = fields
+	return l
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	var entries []entry
+	log := slog.New(slogadapter.NewHandler(recordingLogger{entries: &entries}))
+
+	log.Debug("debug")
+	log.With("a", 1).WithGroup("req").Info("info", "method", "GET", slog.Group("url", "path", "/"))
+	log.Warn("warn %s", "err", fmt.Errorf("oops"))
+	log.Log(context.Background(), slogadapter.LevelFatal, "fatal")
+
+	expected := []entry{
+		{level: "debug", message: "debug"},
+		{level: "info", message: "info", fields: map[string]string{
+			"a":            "1",
+			"req.method":   "GET",
+			"req.url.path": "/",
+		}},
+		{level: "warn", message: "warn %s", fields: map[string]string{"err": "oops"}},
+		{level: "error", message: "fatal"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, entries)
+	}
+}
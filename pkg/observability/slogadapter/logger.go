@@ -0,0 +1,96 @@
+// Package slogadapter connects observability.Logger and log/slog. It
+// registers a Logger that writes to a slog.Handler with the injection system
+// when the package is imported:
+//
+//	import _ "github.com/poy/go-router/pkg/observability/slogadapter"
+//
+// The handler defaults to the one of slog.Default (see AddHandler).
+package slogadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/observability"
+)
+
+func init() {
+	injection.Register[observability.Logger](func(ctx context.Context) observability.Logger {
+		if h, ok := injection.TryResolve[slog.Handler](ctx); ok && h != nil {
+			return New(h)
+		}
+		return New(slog.Default().Handler())
+	})
+}
+
+// AddHandler sets the slog.Handler that the registered logger writes to.
+func AddHandler(h slog.Handler) {
+	injection.Register[slog.Handler](func(ctx context.Context) slog.Handler {
+		return h
+	})
+}
+
+// LevelFatal is the slog.Level that Fatalf entries are written with.
+const LevelFatal = slog.LevelError + 4
+
+// New returns an observability.Logger that writes to h. Fields added with
+// WithField are added as attributes (see slog.Logger.With). The minimum
+// level is decided by h.
+func New(h slog.Handler) observability.Logger {
+	return logger{h: h}
+}
+
+type logger struct {
+	h slog.Handler
+}
+
+// WithField implements Logger.
+func (l logger) WithField(name, value string) observability.Logger {
+	return logger{h: l.h.WithAttrs([]slog.Attr{slog.String(name, value)})}
+}
+
+// Debugf implements Logger.
+func (l logger) Debugf(format string, args ...any) {
+	l.logf(slog.LevelDebug, format, args...)
+}
+
+// Infof implements Logger.
+func (l logger) Infof(format string, args ...any) {
+	l.logf(slog.LevelInfo, format, args...)
+}
+
+// Warnf implements Logger.
+func (l logger) Warnf(format string, args ...any) {
+	l.logf(slog.LevelWarn, format, args...)
+}
+
+// Errorf implements Logger.
+func (l logger) Errorf(format string, args ...any) {
+	l.logf(slog.LevelError, format, args...)
+}
+
+// Fatalf implements Logger. It is written with LevelFatal and then exits.
+func (l logger) Fatalf(format string, args ...any) {
+	l.logf(LevelFatal, format, args...)
+	os.Exit(1)
+}
+
+func (l logger) logf(level slog.Level, format string, args ...any) {
+	ctx := context.Background()
+	if !l.h.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, logf and the exported method so that the
+	// record's source is the caller.
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), pcs[0])
+	_ = l.h.Handle(ctx, r)
+}
@@ -0,0 +1,56 @@
+package slogadapter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/observability"
+	"github.com/poy/go-router/pkg/observability/slogadapter"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelInfo})
+	log := slogadapter.New(h)
+	log.Debugf("dropped")
+	log.WithField("key", "value").Warnf("hello %s", "world")
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected a single JSON entry: %v: %s", err, buf.String())
+	}
+	for k, expected := range map[string]any{
+		"level": "WARN",
+		"msg":   "hello world",
+		"key":   "value",
+	} {
+		if actual := m[k]; actual != expected {
+			t.Errorf("expected %s to be %v, got %v", k, expected, actual)
+		}
+	}
+
+	source, _ := m["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "logger_test.go") {
+		t.Errorf("expected the source to be the caller, got %v", source)
+	}
+}
+
+func TestLogger_injection(t *testing.T) {
+	var buf bytes.Buffer
+	slogadapter.AddHandler(slog.NewTextHandler(&buf, nil))
+
+	ctx := injectiontesting.WithTesting(t)
+	log := injection.Resolve[observability.Logger](ctx)
+	log.WithField("key", "value").Infof("hello")
+
+	if actual := buf.String(); !strings.Contains(actual, "msg=hello key=value") {
+		t.Fatalf("unexpected output: %s", actual)
+	}
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/observability"
+)
+
+// AccessLogOptions configures the access log Modifier.
+type AccessLogOptions struct {
+	// SampleRate is the fraction of requests that are logged (e.g., 0.1
+	// logs about 1 in 10 requests). Values <= 0 or >= 1 log every request.
+	// Requests that result in a 5xx are always logged.
+	SampleRate float64
+
+	// Logger is used to write the entries. It defaults to the Router's
+	// request-scoped logger, which already has the method and route fields.
+	Logger observability.Logger
+}
+
+// AddAccessLog adds a modifier that logs every request (see
+// NewAccessLogModifier).
+func AddAccessLog(opts AccessLogOptions) {
+	injection.Register[injection.Group[Modifier]](
+		func(ctx context.Context) injection.Group[Modifier] {
+			return injection.AddToGroup[Modifier](ctx, NewAccessLogModifier(opts))
+		})
+}
+
+// NewAccessLogModifier returns a modifier that writes one Info entry per
+// request once it has been served. The entry has the method, the matched
+// Route's Path template, the status, the number of bytes written, the
+// latency, the remote address and, if set, the user ID and request ID as
+// fields. Routes with DisableAccessLog set are not logged.
+//
+// The Modifier runs after the request ID Modifier and before the others so
+// that the latency includes them and requests they reject are logged.
+func NewAccessLogModifier(opts AccessLogOptions) Modifier {
+	return Modifier{
+		Name:     AccessLogModifierName,
+		Priority: -90,
+		After:    []string{RequestIDModifierName},
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start := time.Now()
+				next.ServeHTTP(w, r)
+				latency := time.Since(start)
+
+				rw := findResponseWriter(w)
+				if rw == nil {
+					return
+				}
+				route, _ := RouteFromContext(r.Context())
+				if route.DisableAccessLog {
+					return
+				}
+				status := rw.Status()
+				if status == 0 {
+					// Nothing was written, net/http responds with a 200.
+					status = http.StatusOK
+				}
+				if !sampled(opts.SampleRate) && status < http.StatusInternalServerError {
+					return
+				}
+
+				// Prefer the request that was passed to the Handler as
+				// Modifiers (e.g., authentication) add to its context.
+				req := r
+				if rw.req != nil {
+					req = rw.req
+				}
+
+				logger := opts.Logger
+				var scopedFields map[string]string
+				if logger == nil {
+					logger = loggerFor(w)
+					scopedFields = rw.loggerFields
+				} else {
+					logger = logger.
+						WithField("method", r.Method).
+						WithField("route", route.Path)
+				}
+				// The request-scoped logger only has these fields if they
+				// were set by the Router's Modifiers.
+				addField := func(name, value string) {
+					if value != "" && scopedFields[name] != value {
+						logger = logger.WithField(name, value)
+					}
+				}
+				addField("user_id", GetUserID(req.Context()))
+				addField("request_id", RequestIDFromContext(req.Context()))
+				logger = logger.
+					WithField("status", strconv.Itoa(status)).
+					WithField("bytes", strconv.FormatInt(rw.BytesWritten(), 10)).
+					WithField("latency", latency.String()).
+					WithField("remote_addr", r.RemoteAddr)
+				logger.Infof("%s %s %d", r.Method, route.Path, status)
+			})
+		},
+	}
+}
+
+func sampled(rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
@@ -0,0 +1,78 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-router/pkg/observability"
+)
+
+// fieldsLogger records the names of the fields of each Info entry. Unlike
+// most loggers, it keeps duplicate fields.
+type fieldsLogger struct {
+	observability.Logger
+	names   []string
+	entries *[][]string
+}
+
+func (l fieldsLogger) WithField(name, value string) observability.Logger {
+	l.names = append(append([]string(nil), l.names...), name)
+	return l
+}
+
+func (l fieldsLogger) Infof(format string, args ...any) {
+	*l.entries = append(*l.entries, l.names)
+}
+
+func TestAccessLog_requestScopedLogger(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		setter Modifier
+	}{
+		{
+			// E.g., AddContextModifier.
+			name: "context",
+			setter: NewContextModifier(func(ctx context.Context) context.Context {
+				return WithUserID(ctx, "some-user")
+			}),
+		},
+		{
+			// E.g., AddUserInfoModifier.
+			name: "logger field",
+			setter: Modifier{
+				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+					r = withLoggerField(w, r, "user_id", "some-user")
+					return r.WithContext(WithUserID(r.Context(), "some-user"))
+				},
+			},
+		},
+	} {
+		var entries [][]string
+		route := Route{Method: http.MethodGet, Path: "/widgets"}
+		h := chainModifiers(
+			[]Modifier{
+				NewRequestIDModifier(RequestIDOptions{}),
+				NewAccessLogModifier(AccessLogOptions{}),
+				tc.setter,
+			},
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		)
+		h = routeHandler(h, matchedRoute{route: route}, fieldsLogger{entries: &entries}, nil, nil)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/widgets", nil))
+
+		if len(entries) != 1 {
+			t.Fatalf("%s: expected 1 entry, got %v", tc.name, entries)
+		}
+		names := entries[0]
+		sort.Strings(names)
+		if actual, expected := strings.Join(names, ","), "bytes,latency,method,remote_addr,request_id,route,status,user_id"; actual != expected {
+			t.Errorf("%s: expected the fields %s, got %s", tc.name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package router_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/observability"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// recordingLogger is an observability.Logger that records each entry.
+type recordingLogger struct {
+	mu      *sync.Mutex
+	entries *[]logEntry
+	fields  map[string]string
+}
+
+type logEntry struct {
+	level   string
+	message string
+	fields  map[string]string
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{
+		mu:      &sync.Mutex{},
+		entries: &[]logEntry{},
+	}
+}
+
+func (l recordingLogger) record(level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, logEntry{
+		level:   level,
+		message: fmt.Sprintf(format, args...),
+		fields:  l.fields,
+	})
+}
+
+func (l recordingLogger) Entries() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]logEntry(nil), *l.entries...)
+}
+
+func (l recordingLogger) Debugf(format string, args ...any) { l.record("debug", format, args...) }
+func (l recordingLogger) Infof(format string, args ...any)  { l.record("info", format, args...) }
+func (l recordingLogger) Warnf(format string, args ...any)  { l.record("warn", format, args...) }
+func (l recordingLogger) Errorf(format string, args ...any) { l.record("error", format, args...) }
+func (l recordingLogger) Fatalf(format string, args ...any) { l.record("fatal", format, args...) }
+
+func (l recordingLogger) WithField(name, value string) observability.Logger {
+	fields := map[string]string{name: value}
+	for k, v := range l.fields {
+		if k != name {
+			fields[k] = v
+		}
+	}
+	l.fields = fields
+	return l
+}
+
+var (
+	accessLogger      = newRecordingLogger()
+	panicAccessLogger = newRecordingLogger()
+)
+
+func init() {
+	ms := []router.Modifier{
+		router.NewRequestIDModifier(router.RequestIDOptions{}),
+		router.NewAccessLogModifier(router.AccessLogOptions{Logger: accessLogger}),
+		{
+			Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+				return r.WithContext(router.WithUserID(r.Context(), "some-user"))
+			},
+		},
+	}
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:      "/access-log/{id}",
+			Method:    http.MethodGet,
+			Modifiers: ms,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte("hello"))
+			}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:             "/access-log-disabled",
+			Method:           http.MethodGet,
+			Modifiers:        ms,
+			DisableAccessLog: true,
+			Handler:          http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/access-log-panic",
+			Method: http.MethodGet,
+			Modifiers: []router.Modifier{
+				router.NewAccessLogModifier(router.AccessLogOptions{Logger: panicAccessLogger}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("oops")
+			}),
+		})
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/access-log-disabled", nil)
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusOK)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/access-log/123", nil)
+	req.Header.Set("X-Request-Id", "some-id")
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusAccepted)
+
+	entries := accessLogger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", entries)
+	}
+	e := entries[0]
+	if actual, expected := e.message, "GET /access-log/{id} 202"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	for k, expected := range map[string]string{
+		"method":      http.MethodGet,
+		"route":       "/access-log/{id}",
+		"status":      "202",
+		"bytes":       "5",
+		"remote_addr": "10.0.0.1:1234",
+		"user_id":     "some-user",
+		"request_id":  "some-id",
+	} {
+		if actual := e.fields[k]; actual != expected {
+			t.Errorf("expected %s to be %q, got %q", k, expected, actual)
+		}
+	}
+	if e.fields["latency"] == "" {
+		t.Error("expected a latency")
+	}
+}
+
+func TestAccessLog_panic(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/access-log-panic", nil))
+	expectedStatusCode(t, rec, http.StatusInternalServerError)
+
+	entries := panicAccessLogger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", entries)
+	}
+	if actual, expected := entries[0].message, "GET /access-log-panic 500"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,176 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// AuthenticationModifierName is the Name of the authentication Modifier.
+const AuthenticationModifierName = "authentication"
+
+// ErrNoCredentials is returned by an Authenticator when the request doesn't
+// have credentials that it understands. The next Authenticator is tried.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator verifies the credentials of a request.
+type Authenticator interface {
+	// Authenticate returns the verified claims of the request. It returns
+	// ErrNoCredentials if the request doesn't have credentials that it
+	// understands. Any other error rejects the request.
+	Authenticate(*http.Request) (Claims, error)
+}
+
+// AuthenticationOptions configures the authentication Modifier.
+type AuthenticationOptions struct {
+	// Authenticators are tried in order until one of them finds
+	// credentials.
+	Authenticators []Authenticator
+
+	// Optional allows requests without credentials to continue without
+	// any claims. Requests with invalid credentials are still rejected.
+	Optional bool
+}
+
+// AddAuthentication adds a modifier that authenticates every request (see
+// NewAuthenticationModifier).
+func AddAuthentication(opts AuthenticationOptions) {
+	injection.Register[injection.Group[Modifier]](
+		func(ctx context.Context) injection.Group[Modifier] {
+			return injection.AddToGroup[Modifier](ctx, NewAuthenticationModifier(opts))
+		})
+}
+
+// NewAuthenticationModifier returns a modifier that authenticates each
+// request with the given Authenticators. The Principal of the verified
+// claims is stored in the request context (see PrincipalFromContext) and its
+// subject is added as the user_id field of the request-scoped logger.
+// Requests with invalid (or unless Optional, missing) credentials are
+// rejected with a 401. OPTIONS requests are not authenticated so that CORS
+// preflight requests succeed.
+func NewAuthenticationModifier(opts AuthenticationOptions) Modifier {
+	return Modifier{
+		Name: AuthenticationModifierName,
+		Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+			if r.Method == http.MethodOptions {
+				return r
+			}
+
+			for _, a := range opts.Authenticators {
+				claims, err := a.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					loggerFor(w).Warnf("authentication failed: %v", err)
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					return Abort(w, 0, Unauthorized(err))
+				}
+				if sub := claims.String("sub"); sub != "" {
+					r = withLoggerField(w, r, "user_id", sub)
+				}
+				return r.WithContext(withClaims(r.Context(), claims))
+			}
+
+			if opts.Optional {
+				return r
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			return Abort(w, 0, Unauthorized(errors.New("missing credentials")))
+		},
+	}
+}
+
+// Claims are the verified claims of an authenticated request.
+type Claims map[string]any
+
+// String returns the claim as a string. It returns an empty string if the
+// claim is not set.
+func (c Claims) String(name string) string {
+	switch v := c[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Strings returns the claim as a list of strings. Claims that are a single
+// string are split on spaces (e.g., the scope claim).
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case nil:
+		return nil
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, x := range v {
+			values = append(values, fmt.Sprint(x))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// Bool returns the claim as a bool. Strings such as "true" (as sent by some
+// providers for email_verified) are parsed.
+func (c Claims) Bool(name string) bool {
+	switch v := c[name].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// time returns a NumericDate claim (e.g., exp) as a time.
+func (c Claims) time(name string) (time.Time, bool) {
+	var seconds float64
+	switch v := c[name].(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		seconds = f
+	case float64:
+		seconds = v
+	case int64:
+		seconds = float64(v)
+	case int:
+		seconds = float64(v)
+	default:
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true
+}
+
+// ClaimsFromContext returns the verified claims from the request context.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return p.Claims, true
+}
+
+// withClaims returns a new context with the Principal of the claims set.
+func withClaims(ctx context.Context, claims Claims) context.Context {
+	return WithPrincipal(ctx, NewPrincipal(claims))
+}
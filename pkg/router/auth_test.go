@@ -0,0 +1,111 @@
+package router_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/authenticated",
+			Method: http.MethodGet,
+			Modifiers: []router.Modifier{
+				router.NewAuthenticationModifier(router.AuthenticationOptions{
+					Authenticators: []router.Authenticator{
+						router.JWTAuthenticator{
+							Keys: router.StaticKeySet{"rsa": &testRSAKey.PublicKey},
+						},
+						router.APIGatewayAuthenticator{},
+					},
+				}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, ok := router.PrincipalFromContext(r.Context())
+				if !ok {
+					panic("expected a principal")
+				}
+				w.Header().Set("X-User", router.GetUserID(r.Context()))
+				w.Header().Set("X-Email", p.Email)
+			}),
+		})
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	testCases := []struct {
+		name          string
+		headers       map[string]string
+		expectedCode  int
+		expectedUser  string
+		expectedEmail string
+	}{
+		{
+			name: "valid JWT",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signJWT(t, testRSAKey, "rsa", map[string]any{
+					"sub":   "some-user",
+					"email": "user@example.com",
+					"exp":   time.Now().Add(time.Hour).Unix(),
+				}),
+			},
+			expectedCode:  http.StatusOK,
+			expectedUser:  "some-user",
+			expectedEmail: "user@example.com",
+		},
+		{
+			name: "invalid JWT",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signJWT(t, mustGenerateRSAKey(), "rsa", map[string]any{"sub": "some-user"}),
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "gateway user info",
+			headers: map[string]string{
+				router.APIGatewayUserInfoHeader: base64.RawURLEncoding.EncodeToString([]byte(`{"sub": "gateway-user"}`)),
+			},
+			expectedCode: http.StatusOK,
+			expectedUser: "gateway-user",
+		},
+		{
+			name:         "missing credentials",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/authenticated", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(rec, req)
+
+		if actual := rec.Code; actual != tc.expectedCode {
+			t.Errorf("%s: expected status code %d, got %d", tc.name, tc.expectedCode, actual)
+		}
+		if actual := rec.Header().Get("X-User"); actual != tc.expectedUser {
+			t.Errorf("%s: expected user %q, got %q", tc.name, tc.expectedUser, actual)
+		}
+		if actual := rec.Header().Get("X-Email"); actual != tc.expectedEmail {
+			t.Errorf("%s: expected email %q, got %q", tc.name, tc.expectedEmail, actual)
+		}
+		if tc.expectedCode == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected WWW-Authenticate header", tc.name)
+		}
+	}
+}
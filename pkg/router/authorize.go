@@ -0,0 +1,67 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var errUnauthenticated = errors.New("authentication required")
+
+// requiresAuthorization returns true if the Route has any authorization
+// requirements.
+func (r Route) requiresAuthorization() bool {
+	return len(r.RequiredScopes) > 0 ||
+		len(r.RequiredRoles) > 0 ||
+		len(r.RequiredGroups) > 0 ||
+		r.Authorize != nil
+}
+
+// authorize checks the Route's authorization requirements against the
+// request's Principal. The returned error is an HTTPError with a 401 if the
+// request is not authenticated and a 403 if it is not permitted.
+func authorize(r Route, req *http.Request) error {
+	if !r.requiresAuthorization() {
+		return nil
+	}
+
+	p, ok := PrincipalFromContext(req.Context())
+	if !ok {
+		return Unauthorized(errUnauthenticated)
+	}
+
+	var msgs []string
+	for _, req := range []struct {
+		kind     string
+		required []string
+		has      func(string) bool
+	}{
+		{kind: "scopes", required: r.RequiredScopes, has: p.HasScope},
+		{kind: "roles", required: r.RequiredRoles, has: p.HasRole},
+		{kind: "groups", required: r.RequiredGroups, has: p.InGroup},
+	} {
+		var missing []string
+		for _, v := range req.required {
+			if !req.has(v) {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, "missing "+req.kind+": "+strings.Join(missing, ", "))
+		}
+	}
+	if len(msgs) > 0 {
+		return Forbidden(errors.New(strings.Join(msgs, "; ")))
+	}
+
+	if r.Authorize != nil {
+		if err := r.Authorize(p, req); err != nil {
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				return err
+			}
+			return Forbidden(err)
+		}
+	}
+	return nil
+}
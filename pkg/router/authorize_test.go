@@ -0,0 +1,165 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// headerAuthenticator reads the claims as JSON from the X-Test-Claims
+// header.
+type headerAuthenticator struct{}
+
+func (headerAuthenticator) Authenticate(r *http.Request) (router.Claims, error) {
+	data := r.Header.Get("X-Test-Claims")
+	if data == "" {
+		return nil, router.ErrNoCredentials
+	}
+	var claims router.Claims
+	if err := json.Unmarshal([]byte(data), &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func init() {
+	authn := router.NewAuthenticationModifier(router.AuthenticationOptions{
+		Authenticators: []router.Authenticator{headerAuthenticator{}},
+		Optional:       true,
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:           "/authz/scoped",
+			Method:         http.MethodGet,
+			Modifiers:      []router.Modifier{authn},
+			RequiredScopes: []string{"widgets:read"},
+			RequiredRoles:  []string{"viewer"},
+			Handler:        ok,
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:           "/authz/grouped",
+			Method:         http.MethodGet,
+			Modifiers:      []router.Modifier{authn},
+			RequiredGroups: []string{"admins"},
+			Handler:        ok,
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:      "/authz/policy",
+			Method:    http.MethodGet,
+			Modifiers: []router.Modifier{authn},
+			Authorize: func(p *router.Principal, r *http.Request) error {
+				if p.Email == "teapot@example.com" {
+					return router.NewHTTPError(http.StatusTeapot, errors.New("teapot"))
+				}
+				if !p.EmailVerified() {
+					return errors.New("email not verified")
+				}
+				return nil
+			},
+			Handler: ok,
+		})
+	})
+}
+
+func TestAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	testCases := []struct {
+		name         string
+		path         string
+		claims       string
+		expectedCode int
+	}{
+		{name: "unauthenticated", path: "/authz/scoped", expectedCode: http.StatusUnauthorized},
+		{name: "missing scope", path: "/authz/scoped", claims: `{"sub": "a", "roles": ["viewer"]}`, expectedCode: http.StatusForbidden},
+		{name: "missing role", path: "/authz/scoped", claims: `{"sub": "a", "scope": "widgets:read"}`, expectedCode: http.StatusForbidden},
+		{name: "role from group", path: "/authz/scoped", claims: `{"sub": "a", "scope": "widgets:read", "groups": ["viewer"]}`, expectedCode: http.StatusForbidden},
+		{name: "permitted", path: "/authz/scoped", claims: `{"sub": "a", "scope": "widgets:write widgets:read", "roles": ["viewer"]}`, expectedCode: http.StatusOK},
+		{name: "missing group", path: "/authz/grouped", claims: `{"sub": "a", "roles": ["admins"]}`, expectedCode: http.StatusForbidden},
+		{name: "in group", path: "/authz/grouped", claims: `{"sub": "a", "groups": ["admins"]}`, expectedCode: http.StatusOK},
+		{name: "policy unauthenticated", path: "/authz/policy", expectedCode: http.StatusUnauthorized},
+		{name: "policy denied", path: "/authz/policy", claims: `{"sub": "a"}`, expectedCode: http.StatusForbidden},
+		{name: "policy HTTPError", path: "/authz/policy", claims: `{"sub": "a", "email": "teapot@example.com"}`, expectedCode: http.StatusTeapot},
+		{name: "policy permitted", path: "/authz/policy", claims: `{"sub": "a", "email_verified": true}`, expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.claims != "" {
+			req.Header.Set("X-Test-Claims", tc.claims)
+		}
+		r.ServeHTTP(rec, req)
+
+		if actual := rec.Code; actual != tc.expectedCode {
+			t.Errorf("%s: expected status code %d, got %d: %s", tc.name, tc.expectedCode, actual, rec.Body.String())
+		}
+		if actual := rec.Header().Get("WWW-Authenticate"); (actual != "") != (tc.expectedCode == http.StatusUnauthorized) {
+			t.Errorf("%s: unexpected WWW-Authenticate header %q", tc.name, actual)
+		}
+	}
+}
+
+func TestAuthorization_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	expectedStatusCode(t, rec, http.StatusOK)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Security  []map[string][]string `json:"security"`
+			Responses map[string]any        `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			SecuritySchemes map[string]map[string]string `json:"securitySchemes"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := doc.Components.SecuritySchemes["bearerAuth"]["scheme"], "bearer"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	op := doc.Paths["/authz/scoped"]["get"]
+	expected := []map[string][]string{{"bearerAuth": {"widgets:read", "viewer"}}}
+	if !reflect.DeepEqual(op.Security, expected) {
+		t.Fatalf("expected %v, got %v", expected, op.Security)
+	}
+	for _, code := range []string{"401", "403"} {
+		if _, ok := op.Responses[code]; !ok {
+			t.Fatalf("expected a %s response", code)
+		}
+	}
+
+	// OpenAPI requires an array even if the Route only sets Authorize.
+	security := doc.Paths["/authz/policy"]["get"].Security
+	if len(security) != 1 {
+		t.Fatalf("expected a security requirement, got %v", security)
+	}
+	if scopes, ok := security[0]["bearerAuth"]; !ok || scopes == nil || len(scopes) != 0 {
+		t.Fatalf("expected an empty list of scopes, got %#v", scopes)
+	}
+}
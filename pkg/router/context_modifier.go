@@ -10,10 +10,17 @@ import (
 func AddContextModifier(f func(context.Context) context.Context) {
 	injection.Register[injection.Group[Modifier]](
 		func(ctx context.Context) injection.Group[Modifier] {
-			return injection.AddToGroup[Modifier](ctx, Modifier{
-				Pre: func(rec http.ResponseWriter, req *http.Request) *http.Request {
-					return req.WithContext(f(req.Context()))
-				},
-			})
+			return injection.AddToGroup[Modifier](ctx, NewContextModifier(f))
 		})
 }
+
+// NewContextModifier returns a modifier that replaces each request's context
+// with the one returned by f.
+func NewContextModifier(f func(context.Context) context.Context) Modifier {
+	return Modifier{
+		Name: ContextModifierName,
+		Pre: func(rec http.ResponseWriter, req *http.Request) *http.Request {
+			return req.WithContext(f(req.Context()))
+		},
+	}
+}
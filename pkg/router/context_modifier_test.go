@@ -10,12 +10,22 @@ import (
 	"github.com/poy/go-router/pkg/router"
 )
 
+func init() {
+	// The PathPrefix keeps the Modifier from applying to the other tests'
+	// Routes.
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewContextModifier(func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, "foo", "bar")
+			})
+			m.PathPrefix = "/context-modifier/"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+}
+
 func TestAddContextModifier(t *testing.T) {
 	t.Parallel()
 
-	router.AddContextModifier(func(ctx context.Context) context.Context {
-		return context.WithValue(ctx, "foo", "bar")
-	})
 	ctx := injectiontesting.WithTesting(t)
 	modifiers := injection.Resolve[injection.Group[router.Modifier]](ctx).Vals()
 	rec := httptest.NewRecorder()
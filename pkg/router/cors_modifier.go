@@ -3,21 +3,185 @@ package router
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/poy/go-dependency-injection/pkg/injection"
 )
 
-// AddCorsHeaders adds the CORS headers to the response.
+// CORSPolicy configures how the Router responds to cross-origin requests.
+type CORSPolicy struct {
+	// AllowedOrigins are the origins that may make cross-origin requests.
+	// "*" allows any origin and a wildcard subdomain (e.g.,
+	// https://*.example.com) allows any subdomain of the given domain. The
+	// matched origin is reflected in the response.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods allowed by a preflight request. If
+	// empty, the methods registered for the requested Route's Path are used.
+	AllowedMethods []string
+
+	// AllowedHeaders are the request headers allowed by a preflight request.
+	// If empty, a set of common headers is allowed.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers that the client may read.
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests to include credentials (e.g.,
+	// cookies). The origin is always reflected (rather than "*") when set.
+	AllowCredentials bool
+
+	// MaxAge is how long the result of a preflight request may be cached.
+	// It is omitted if zero.
+	MaxAge time.Duration
+}
+
+var defaultCORSAllowedHeaders = []string{
+	"Accept",
+	"Content-Type",
+	"Content-Length",
+	"Accept-Encoding",
+	"X-CSRF-Token",
+	"Authorization",
+}
+
+// AddCORSModifier adds a CORS modifier that allows the given origin.
+//
+// Deprecated: Use AddCORSPolicy.
 func AddCORSModifier(cors string) {
+	AddCORSPolicy(CORSPolicy{
+		AllowedOrigins: []string{cors},
+	})
+}
+
+// AddCORSPolicy adds a modifier that applies the CORS policy to every Route.
+func AddCORSPolicy(p CORSPolicy) {
 	injection.Register[injection.Group[Modifier]](
 		func(ctx context.Context) injection.Group[Modifier] {
-			return injection.AddToGroup[Modifier](ctx, Modifier{
-				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
-					w.Header().Add("Access-Control-Allow-Origin", cors)
-					w.Header().Add("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-					w.Header().Add("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-					return r
-				},
-			})
+			return injection.AddToGroup[Modifier](ctx, NewCORSModifier(p))
 		})
 }
+
+// NewCORSModifier returns a modifier that applies the CORS policy. Preflight
+// requests from allowed origins are answered with a 204 without invoking the
+// Modifiers that follow it. The Modifier has a low Priority so that it runs
+// before the Modifiers that may reject a request (e.g., authentication) and
+// browsers can read their errors.
+func NewCORSModifier(p CORSPolicy) Modifier {
+	allowedHeaders := p.AllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = defaultCORSAllowedHeaders
+	}
+	allowedHeadersStr := strings.Join(allowedHeaders, ", ")
+	exposedHeadersStr := strings.Join(p.ExposedHeaders, ", ")
+
+	return Modifier{
+		Name:     CORSModifierName,
+		Priority: -50,
+		Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+			addVary(w.Header(), "Origin")
+
+			origin := r.Header.Get("Origin")
+			allowAny, ok := p.matchOrigin(origin)
+			if !ok {
+				return r
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			methods := p.AllowedMethods
+			if len(methods) == 0 {
+				methods = allowedMethodsFromContext(r.Context())
+			}
+			if preflight && !containsFold(methods, r.Header.Get("Access-Control-Request-Method")) {
+				// Without the CORS headers the browser will reject the
+				// request.
+				return r
+			}
+
+			h := w.Header()
+			if allowAny && !p.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if p.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposedHeadersStr != "" {
+					h.Set("Access-Control-Expose-Headers", exposedHeadersStr)
+				}
+				return r
+			}
+
+			addVary(h, "Access-Control-Request-Method")
+			addVary(h, "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			h.Set("Access-Control-Allow-Headers", allowedHeadersStr)
+			if p.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		},
+	}
+}
+
+// matchOrigin reports whether the origin is allowed and if it was allowed by
+// "*".
+func (p CORSPolicy) matchOrigin(origin string) (allowAny, ok bool) {
+	if origin == "" {
+		return false, false
+	}
+
+	for _, allowed := range p.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			return true, true
+		case strings.EqualFold(allowed, origin):
+			return false, true
+		case strings.Contains(allowed, "://*."):
+			if matchWildcardOrigin(allowed, origin) {
+				return false, true
+			}
+		}
+	}
+	return false, false
+}
+
+// matchWildcardOrigin matches origins such as https://api.example.com
+// against patterns such as https://*.example.com.
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, domain, _ := strings.Cut(pattern, "://*.")
+	u, err := url.Parse(origin)
+	if err != nil || !strings.EqualFold(u.Scheme, scheme) {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	domain = strings.ToLower(domain)
+	return strings.HasSuffix(host, "."+domain) && len(host) > len(domain)+1
+}
+
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		for _, existing := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,22 +1,87 @@
 package router_test
 
 import (
+	"context"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/poy/go-dependency-injection/pkg/injection"
 	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
 	"github.com/poy/go-router/pkg/router"
 )
 
+func init() {
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		method := method
+		injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+			return injection.AddToGroup[router.Route](ctx, router.Route{
+				Path:   "/cors/widgets/{id}",
+				Method: method,
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			})
+		})
+	}
+
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewCORSModifier(router.CORSPolicy{
+				AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+				ExposedHeaders:   []string{"X-Total"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			})
+			m.PathPrefix = "/cors/"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewCORSModifier(router.CORSPolicy{AllowedOrigins: []string{"some-cors"}})
+			m.PathPrefix = "/cors-modifier/"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/route-cors",
+			Method: http.MethodPut,
+			Modifiers: []router.Modifier{
+				router.NewCORSModifier(router.CORSPolicy{
+					AllowedOrigins: []string{"https://app.example.com"},
+				}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:    "/cors/secret",
+			Method:  http.MethodGet,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewAuthenticationModifier(router.AuthenticationOptions{
+				Authenticators: []router.Authenticator{headerAuthenticator{}},
+				Optional:       true,
+			})
+			m.PathPrefix = "/cors/secret"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+}
+
 func TestCorsModifier(t *testing.T) {
 	t.Parallel()
 
-	router.AddCORSModifier("some-cors")
 	ctx := injectiontesting.WithTesting(t)
 	modifiers := injection.Resolve[injection.Group[router.Modifier]](ctx).Vals()
 	rec := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Origin", "some-cors")
 	for _, modifier := range modifiers {
 		if modifier.Pre == nil {
 			continue
@@ -28,3 +93,143 @@ func TestCorsModifier(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, actual)
 	}
 }
+
+func TestCORSPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	testCases := []struct {
+		name           string
+		method         string
+		origin         string
+		requestMethod  string
+		expectedCode   int
+		expectedHeader map[string]string
+	}{
+		{
+			name:         "allowed origin",
+			method:       http.MethodGet,
+			origin:       "https://app.example.com",
+			expectedCode: http.StatusOK,
+			expectedHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Total",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:         "wildcard subdomain",
+			method:       http.MethodGet,
+			origin:       "https://api.example.org",
+			expectedCode: http.StatusOK,
+			expectedHeader: map[string]string{
+				"Access-Control-Allow-Origin": "https://api.example.org",
+			},
+		},
+		{
+			name:         "disallowed origin",
+			method:       http.MethodGet,
+			origin:       "https://example.org",
+			expectedCode: http.StatusOK,
+			expectedHeader: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:          "preflight",
+			method:        http.MethodOptions,
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodPut,
+			expectedCode:  http.StatusNoContent,
+			expectedHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, PUT",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:          "preflight for unregistered method",
+			method:        http.MethodOptions,
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodDelete,
+			expectedCode:  http.StatusOK,
+			expectedHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, "/cors/widgets/123", nil)
+		req.Header.Set("Origin", tc.origin)
+		if tc.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+		}
+		r.ServeHTTP(rec, req)
+
+		if actual := rec.Code; actual != tc.expectedCode {
+			t.Errorf("%s: expected status code %d, got %d", tc.name, tc.expectedCode, actual)
+		}
+		for k, expected := range tc.expectedHeader {
+			if actual := rec.Header().Get(k); actual != expected {
+				t.Errorf("%s: expected %s to be %q, got %q", tc.name, k, expected, actual)
+			}
+		}
+	}
+}
+
+func TestCORSPolicy_unauthorized(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/cors/secret", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("X-Test-Claims", "invalid")
+	r.ServeHTTP(rec, req)
+
+	expectedStatusCode(t, rec, http.StatusUnauthorized)
+	if actual, expected := rec.Header().Get("Access-Control-Allow-Origin"), "https://app.example.com"; actual != expected {
+		t.Fatalf("expected the error to have the CORS headers, got %q", actual)
+	}
+}
+
+func TestCORSPolicy_routeModifier(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	preflight := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/route-cors", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", method)
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := preflight(http.MethodPut)
+	expectedStatusCode(t, rec, http.StatusNoContent)
+	if actual, expected := rec.Header().Get("Access-Control-Allow-Origin"), "https://app.example.com"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPut; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	// The Route's Modifiers only apply to preflight requests for it.
+	rec = preflight(http.MethodDelete)
+	expectedStatusCode(t, rec, http.StatusOK)
+	if actual := rec.Header().Get("Access-Control-Allow-Origin"); actual != "" {
+		t.Fatalf("expected no CORS headers, got %q", actual)
+	}
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// HTTPError is an error that knows how it should be written to the client.
+// WriteError (and therefore Handle) uses it to decide the status code and
+// body of the response.
+type HTTPError struct {
+	// Status is the HTTP status code.
+	Status int
+
+	// Code is a machine-readable error code (e.g., not_found).
+	Code string
+
+	// Message is the message that is written to the client.
+	Message string
+
+	// Details are additional (client-safe) values that are written with the
+	// error.
+	Details map[string]any
+
+	// Err is the underlying cause. It is only written to the client for 4xx
+	// errors that don't have a Message.
+	Err error
+}
+
+// NewHTTPError returns an HTTPError for the given status code and cause. The
+// Code is derived from the status code (e.g., 404 is not_found). The Message
+// is the cause's message for 4xx errors and the status text otherwise so
+// that internal errors are not leaked to clients.
+func NewHTTPError(status int, err error) *HTTPError {
+	e := &HTTPError{
+		Status: status,
+		Code:   statusCode(status),
+		Err:    err,
+	}
+	if err != nil && status < http.StatusInternalServerError {
+		e.Message = err.Error()
+	} else {
+		e.Message = http.StatusText(status)
+	}
+	return e
+}
+
+// Error implements error.
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" || e.Message == e.Err.Error() {
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithDetail returns a copy of the error with the given detail added.
+func (e *HTTPError) WithDetail(key string, value any) *HTTPError {
+	c := *e
+	c.Details = make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		c.Details[k] = v
+	}
+	c.Details[key] = value
+	return &c
+}
+
+// BadRequest returns a 400 HTTPError.
+func BadRequest(err error) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, err)
+}
+
+// Unauthorized returns a 401 HTTPError.
+func Unauthorized(err error) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, err)
+}
+
+// Forbidden returns a 403 HTTPError.
+func Forbidden(err error) *HTTPError {
+	return NewHTTPError(http.StatusForbidden, err)
+}
+
+// NotFound returns a 404 HTTPError.
+func NotFound(err error) *HTTPError {
+	return NewHTTPError(http.StatusNotFound, err)
+}
+
+// Conflict returns a 409 HTTPError.
+func Conflict(err error) *HTTPError {
+	return NewHTTPError(http.StatusConflict, err)
+}
+
+// UnprocessableEntity returns a 422 HTTPError.
+func UnprocessableEntity(err error) *HTTPError {
+	return NewHTTPError(http.StatusUnprocessableEntity, err)
+}
+
+// Internal returns a 500 HTTPError. The cause is logged but not written to
+// the client.
+func Internal(err error) *HTTPError {
+	return NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// StatusCode returns the HTTP status code for the given error. It is the
+// Status of an HTTPError (found via errors.As), 422 for ValidationErrors and
+// 500 otherwise.
+func StatusCode(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status != 0 {
+		return httpErr.Status
+	}
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// statusCode converts a status code into a machine-readable code (e.g., 404
+// is not_found).
+func statusCode(status int) string {
+	text := strings.ToLower(http.StatusText(status))
+	if text == "" {
+		return ""
+	}
+	text = strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text)
+	return text
+}
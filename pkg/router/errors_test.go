@@ -0,0 +1,97 @@
+package router_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/poy/go-router/pkg/router"
+)
+
+func TestHTTPError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("widget 123 not found")
+	err := fmt.Errorf("wrapped: %w", router.NotFound(cause).WithDetail("id", "123"))
+
+	var httpErr *router.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatal("expected an HTTPError")
+	}
+	if actual, expected := httpErr.Status, http.StatusNotFound; actual != expected {
+		t.Fatalf("expected %d, got %d", expected, actual)
+	}
+	if actual, expected := httpErr.Code, "not_found"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected the cause to be unwrapped")
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{err: router.Forbidden(errors.New("nope")), expected: http.StatusForbidden},
+		{err: fmt.Errorf("wrapped: %w", router.Conflict(nil)), expected: http.StatusConflict},
+		{err: router.ValidationErrors{{Field: "a", Message: "b"}}, expected: http.StatusUnprocessableEntity},
+		{err: errors.New("some-error"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		if actual := router.StatusCode(tc.err); actual != tc.expected {
+			t.Errorf("%v: expected %d, got %d", tc.err, tc.expected, actual)
+		}
+	}
+}
+
+func TestWriteError_HTTPError(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	router.WriteError(recorder, 0, router.NotFound(errors.New("widget not found")).WithDetail("id", "123"))
+	expectedStatusCode(t, recorder, http.StatusNotFound)
+	expectedContentType(t, recorder, "application/json")
+
+	var m map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"error":   "widget not found",
+		"code":    "not_found",
+		"details": map[string]any{"id": "123"},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %v, got %v", expected, m)
+	}
+}
+
+func TestWriteError_InternalNotLeaked(t *testing.T) {
+	t.Parallel()
+
+	for _, err := range []error{
+		errors.New("password=hunter2"),
+		router.Internal(errors.New("password=hunter2")),
+	} {
+		recorder := httptest.NewRecorder()
+		router.WriteError(recorder, 0, err)
+		expectedStatusCode(t, recorder, http.StatusInternalServerError)
+
+		var m map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := m["error"], http.StatusText(http.StatusInternalServerError); actual != expected {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
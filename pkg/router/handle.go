@@ -0,0 +1,145 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// Handle returns an http.Handler that decodes the JSON request body into a
+// TReq, invokes fn and writes the returned TResp as JSON.
+//
+// Fields of TReq with a path tag (e.g., `path:"id" json:"-"`) are set from
+// the path variables of the request. An empty body leaves TReq with only
+// its path variables set. TReq is validated (see Validate) before fn is
+// invoked.
+//
+// Requests that can't be decoded are rejected with a 400 and invalid ones
+// with a 422. Errors returned by fn are written with WriteError, so an
+// HTTPError (e.g., NotFound(err)) decides the status code. Any other error
+// results in a 500.
+//
+// When used as a Route's Handler, the Route's RequestSchema and
+// ResponseSchema default to TReq and TResp in the OpenAPI V3 spec.
+func Handle[TReq, TResp any](fn func(context.Context, TReq) (TResp, error)) http.Handler {
+	return typedHandler[TReq, TResp]{fn: fn}
+}
+
+type typedHandler[TReq, TResp any] struct {
+	fn func(context.Context, TReq) (TResp, error)
+}
+
+// schemaProvider is implemented by handlers that know their request and
+// response types.
+type schemaProvider interface {
+	requestSchema() any
+	responseSchema() any
+}
+
+var _ schemaProvider = typedHandler[int, int]{}
+
+func (h typedHandler[TReq, TResp]) requestSchema() any {
+	var req TReq
+	return req
+}
+
+func (h typedHandler[TReq, TResp]) responseSchema() any {
+	var resp TResp
+	return resp
+}
+
+// ServeHTTP implements http.Handler.
+func (h typedHandler[TReq, TResp]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req TReq
+	if r.Body != nil {
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			WriteError(w, 0, BadRequest(fmt.Errorf("invalid request body: %v", err)))
+			return
+		}
+	}
+
+	vars, _ := r.Context().Value(pathVarKey{}).(map[string]string)
+	if err := setPathVars(&req, vars); err != nil {
+		WriteError(w, 0, BadRequest(err))
+		return
+	}
+
+	if err := Validate(req); err != nil {
+		WriteError(w, 0, err)
+		return
+	}
+
+	resp, err := h.fn(r.Context(), req)
+	if err != nil {
+		WriteError(w, 0, err)
+		return
+	}
+
+	WriteResponse(w, resp)
+}
+
+// setPathVars sets each field of the struct pointed to by ptr that has a
+// path tag to the corresponding path variable.
+func setPathVars(ptr any, vars map[string]string) error {
+	v := reflect.ValueOf(ptr).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := f.Tag.Lookup("path")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		if err := setFromString(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid path variable %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func setFromString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+type typedRequest struct {
+	ID    int    `path:"id" json:"-"`
+	Name  string `json:"name" validate:"max=3"`
+	Fails bool   `json:"fails"`
+}
+
+type typedResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func typedHandler(ctx context.Context, req typedRequest) (typedResponse, error) {
+	if req.Fails {
+		return typedResponse{}, errors.New("some-error")
+	}
+	return typedResponse{ID: req.ID, Name: req.Name}, nil
+}
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:    "/typed/{id}",
+			Method:  http.MethodGet,
+			Handler: router.Handle(typedHandler),
+		})
+	})
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	router.Handle(typedHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "abc"}`)))
+	expectedStatusCode(t, rec, http.StatusOK)
+	expectedContentType(t, rec, "application/json")
+
+	var resp typedResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (typedResponse{Name: "abc"}); resp != expected {
+		t.Fatalf("expected %+v, got %+v", expected, resp)
+	}
+}
+
+func TestHandle_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "malformed", body: `{"name":`, expectedCode: http.StatusBadRequest},
+		{name: "invalid", body: `{"name": "abcd"}`, expectedCode: http.StatusUnprocessableEntity},
+		{name: "handler error", body: `{"fails": true}`, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		// Avoid issues with closure.
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			router.Handle(typedHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body)))
+			expectedStatusCode(t, rec, tc.expectedCode)
+			expectedContentType(t, rec, "application/json")
+		})
+	}
+}
+
+func TestHandle_pathVars(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/typed/123", nil))
+	expectedStatusCode(t, rec, http.StatusOK)
+
+	var resp typedResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (typedResponse{ID: 123}); resp != expected {
+		t.Fatalf("expected %+v, got %+v", expected, resp)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/typed/abc", nil))
+	expectedStatusCode(t, rec, http.StatusBadRequest)
+}
+
+func TestHandle_ResponseSchema(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	expectedStatusCode(t, rec, http.StatusOK)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]any `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	props := doc.Paths["/typed/{id}"]["get"].Responses["200"].Content["application/json"].Schema.Properties
+	expected := map[string]any{
+		"id":   map[string]any{"type": "integer", "format": "int64"},
+		"name": map[string]any{"type": "string"},
+	}
+	if !reflect.DeepEqual(props, expected) {
+		t.Fatalf("expected %v, got %v", expected, props)
+	}
+}
@@ -0,0 +1,253 @@
+package router
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	// Register the hashes used by the supported algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// KeySet provides the keys used to verify JWT signatures.
+type KeySet interface {
+	// VerificationKeys returns the keys that may have signed a token with
+	// the given key ID (kid). The kid is empty if the token doesn't have
+	// one.
+	VerificationKeys(kid string) []any
+}
+
+// StaticKeySet is a KeySet of keys indexed by their key ID. The values are
+// *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte (for HMAC).
+// Tokens without a kid are verified against every key.
+type StaticKeySet map[string]any
+
+// VerificationKeys implements KeySet.
+func (s StaticKeySet) VerificationKeys(kid string) []any {
+	if kid != "" {
+		if key, ok := s[kid]; ok {
+			return []any{key}
+		}
+		return nil
+	}
+
+	keys := make([]any, 0, len(s))
+	for _, key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// JWTAuthenticator is an Authenticator that verifies a bearer JWT from the
+// Authorization header.
+type JWTAuthenticator struct {
+	// Keys are used to verify the token's signature.
+	Keys KeySet
+
+	// Issuer is the required iss claim. It is not checked if empty.
+	Issuer string
+
+	// Audience must be one of the token's aud claims. It is not checked if
+	// empty.
+	Audience string
+
+	// Leeway is the allowed clock skew when checking exp and nbf.
+	Leeway time.Duration
+
+	// AllowMissingExpiry accepts tokens without an exp claim. Such tokens
+	// never expire, so they are rejected by default.
+	AllowMissingExpiry bool
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+var _ Authenticator = JWTAuthenticator{}
+
+// Authenticate implements Authenticator.
+func (a JWTAuthenticator) Authenticate(r *http.Request) (Claims, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+	return a.Verify(strings.TrimSpace(token))
+}
+
+// Verify verifies the token's signature and claims and returns the claims.
+func (a JWTAuthenticator) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+
+	if a.Keys == nil {
+		return nil, errors.New("no keys configured")
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range a.Keys.VerificationKeys(header.Kid) {
+		if err := verifyJWTSignature(header.Alg, key, signed, sig); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid token signature")
+	}
+
+	var claims Claims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	if err := a.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (a JWTAuthenticator) checkClaims(claims Claims) error {
+	now := time.Now()
+	if a.Now != nil {
+		now = a.Now()
+	}
+
+	exp, ok := claims.time("exp")
+	if !ok && !a.AllowMissingExpiry {
+		return errors.New("token has no expiry")
+	}
+	if ok && !now.Before(exp.Add(a.Leeway)) {
+		return errors.New("token has expired")
+	}
+	if nbf, ok := claims.time("nbf"); ok && now.Add(a.Leeway).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+	if a.Issuer != "" && claims.String("iss") != a.Issuer {
+		return fmt.Errorf("invalid token issuer %q", claims.String("iss"))
+	}
+	if a.Audience != "" && !containsString(claims.Strings("aud"), a.Audience) {
+		return errors.New("invalid token audience")
+	}
+	return nil
+}
+
+func decodeJWTSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// verifyJWTSignature verifies the signature with the given algorithm. The
+// key's type must match the algorithm so that, for example, an RSA public
+// key can't be used as an HMAC secret.
+func verifyJWTSignature(alg string, key any, signed, sig []byte) error {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return errKeyMismatch
+		}
+		mac := hmac.New(jwtHash(alg).New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return errInvalidSignature
+		}
+		return nil
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errKeyMismatch
+		}
+		h := jwtHash(alg)
+		digest := hashSum(h.New(), signed)
+		if alg[0] == 'P' {
+			return rsa.VerifyPSS(pub, h, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(pub, h, digest, sig)
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errKeyMismatch
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errInvalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, hashSum(jwtHash(alg).New(), signed), r, s) {
+			return errInvalidSignature
+		}
+		return nil
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errKeyMismatch
+		}
+		if !ed25519.Verify(pub, signed, sig) {
+			return errInvalidSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+}
+
+var (
+	errKeyMismatch      = errors.New("key does not match algorithm")
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+func jwtHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func hashSum(h hash.Hash, data []byte) []byte {
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
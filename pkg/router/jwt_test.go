@@ -0,0 +1,259 @@
+package router_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poy/go-router/pkg/router"
+)
+
+var (
+	testRSAKey = mustGenerateRSAKey()
+	testECKey  = mustGenerateECKey()
+	testSecret = []byte("some-secret")
+)
+
+func mustGenerateRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func mustGenerateECKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// signJWT returns a JWT signed with the given key. The algorithm is decided
+// by the key's type.
+func signJWT(t *testing.T, key any, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header := map[string]any{"typ": "JWT", "kid": kid}
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		header["alg"] = "RS256"
+	case *ecdsa.PrivateKey:
+		header["alg"] = "ES256"
+	case []byte:
+		header["alg"] = "HS256"
+	}
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTAuthenticator_Verify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	a := router.JWTAuthenticator{
+		Keys: router.StaticKeySet{
+			"rsa":  &testRSAKey.PublicKey,
+			"ec":   &testECKey.PublicKey,
+			"hmac": testSecret,
+		},
+		Issuer:   "https://issuer.example.com",
+		Audience: "some-audience",
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return now },
+	}
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"sub": "some-user",
+			"iss": "https://issuer.example.com",
+			"aud": []string{"other-audience", "some-audience"},
+			"exp": now.Add(time.Hour).Unix(),
+			"nbf": now.Add(-time.Hour).Unix(),
+		}
+	}
+	withClaim := func(k string, v any) map[string]any {
+		c := validClaims()
+		c[k] = v
+		return c
+	}
+	withoutClaim := func(k string) map[string]any {
+		c := validClaims()
+		delete(c, k)
+		return c
+	}
+
+	testCases := []struct {
+		name        string
+		key         any
+		kid         string
+		claims      map[string]any
+		expectedErr string
+	}{
+		{name: "RS256", key: testRSAKey, kid: "rsa", claims: validClaims()},
+		{name: "ES256", key: testECKey, kid: "ec", claims: validClaims()},
+		{name: "HS256", key: testSecret, kid: "hmac", claims: validClaims()},
+		{name: "without kid", key: testRSAKey, claims: validClaims()},
+		{name: "within leeway", key: testRSAKey, kid: "rsa", claims: withClaim("exp", now.Add(-30*time.Second).Unix())},
+		{name: "wrong key", key: mustGenerateRSAKey(), kid: "rsa", claims: validClaims(), expectedErr: "invalid token signature"},
+		{name: "unknown kid", key: testRSAKey, kid: "unknown", claims: validClaims(), expectedErr: "invalid token signature"},
+		{name: "HMAC with RSA key", key: testSecret, kid: "rsa", claims: validClaims(), expectedErr: "invalid token signature"},
+		{name: "expired", key: testRSAKey, kid: "rsa", claims: withClaim("exp", now.Add(-time.Hour).Unix()), expectedErr: "token has expired"},
+		{name: "without expiry", key: testRSAKey, kid: "rsa", claims: withoutClaim("exp"), expectedErr: "token has no expiry"},
+		{name: "invalid expiry", key: testRSAKey, kid: "rsa", claims: withClaim("exp", "tomorrow"), expectedErr: "token has no expiry"},
+		{name: "not valid yet", key: testRSAKey, kid: "rsa", claims: withClaim("nbf", now.Add(time.Hour).Unix()), expectedErr: "token is not valid yet"},
+		{name: "wrong issuer", key: testRSAKey, kid: "rsa", claims: withClaim("iss", "evil"), expectedErr: `invalid token issuer "evil"`},
+		{name: "wrong audience", key: testRSAKey, kid: "rsa", claims: withClaim("aud", "other-audience"), expectedErr: "invalid token audience"},
+	}
+
+	for _, tc := range testCases {
+		claims, err := a.Verify(signJWT(t, tc.key, tc.kid, tc.claims))
+		if tc.expectedErr != "" {
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("%s: expected error %q, got %v", tc.name, tc.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if actual, expected := claims.String("sub"), "some-user"; actual != expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, expected, actual)
+		}
+	}
+}
+
+func TestJWTAuthenticator_AllowMissingExpiry(t *testing.T) {
+	t.Parallel()
+
+	a := router.JWTAuthenticator{
+		Keys:               router.StaticKeySet{"rsa": &testRSAKey.PublicKey},
+		AllowMissingExpiry: true,
+	}
+	claims, err := a.Verify(signJWT(t, testRSAKey, "rsa", map[string]any{"sub": "some-user"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := claims.String("sub"), "some-user"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestJWTAuthenticator_VerifyMalformed(t *testing.T) {
+	t.Parallel()
+
+	a := router.JWTAuthenticator{Keys: router.StaticKeySet{"": testSecret}}
+	for _, token := range []string{"", "a.b", "a.b.c", "e30.e30.!!"} {
+		if _, err := a.Verify(token); err == nil {
+			t.Errorf("%q: expected an error", token)
+		}
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	t.Parallel()
+
+	b64 := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "hmac", "k": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`,
+		b64(testRSAKey.N), b64(big.NewInt(int64(testRSAKey.E))),
+		b64(testECKey.X), b64(testECKey.Y),
+		base64.RawURLEncoding.EncodeToString(testSecret),
+	)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := router.LoadJWKSFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := len(keys), 3; actual != expected {
+		t.Fatalf("expected %d keys, got %d", expected, actual)
+	}
+
+	a := router.JWTAuthenticator{Keys: keys}
+	for kid, key := range map[string]any{"rsa": testRSAKey, "ec": testECKey, "hmac": testSecret} {
+		claims := map[string]any{"sub": "some-user", "exp": time.Now().Add(time.Hour).Unix()}
+		if _, err := a.Verify(signJWT(t, key, kid, claims)); err != nil {
+			t.Errorf("%s: unexpected error: %v", kid, err)
+		}
+	}
+}
+
+func TestLoadJWKSFile_invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := router.ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-1"}]}`)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := router.LoadJWKSFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// Keys that share a kid would replace each other.
+	for _, kids := range [][2]string{{"a", "a"}, {"", ""}} {
+		data := fmt.Sprintf(`{"keys": [{"kty": "oct", "kid": %q, "k": "c2VjcmV0"}, {"kty": "oct", "kid": %q, "k": "b3RoZXI"}]}`, kids[0], kids[1])
+		if _, err := router.ParseJWKS([]byte(data)); err == nil || !strings.Contains(err.Error(), "duplicate kid") {
+			t.Fatalf("%q: expected a duplicate kid error, got %v", kids, err)
+		}
+	}
+	if _, err := router.ParseJWKS([]byte(`{"keys": [{"kty": "oct", "kid": "a", "k": "c2VjcmV0"}, {"kty": "oct", "k": "b3RoZXI"}]}`)); err != nil {
+		t.Fatalf("expected different kids to be allowed, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/poy/go-dependency-injection/pkg/injection"
@@ -11,11 +12,33 @@ import (
 func AddLimitRequestBody(size int) {
 	injection.Register[injection.Group[Modifier]](
 		func(ctx context.Context) injection.Group[Modifier] {
-			return injection.AddToGroup[Modifier](ctx, Modifier{
-				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
-					r.Body = http.MaxBytesReader(w, r.Body, int64(size))
-					return r
-				},
-			})
+			return injection.AddToGroup[Modifier](ctx, NewLimitRequestBodyModifier(size))
 		})
 }
+
+// NewLimitRequestBodyModifier returns a modifier that limits the request body
+// size. It replaces any limit set by a previous limit modifier, so a Route
+// can use it to allow a larger body than the registered limit.
+func NewLimitRequestBodyModifier(size int) Modifier {
+	return Modifier{
+		Name: LimitRequestBodyModifierName,
+		Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+			body := r.Body
+			if lb, ok := body.(*limitedBody); ok {
+				body = lb.orig
+			}
+			r.Body = &limitedBody{
+				ReadCloser: http.MaxBytesReader(w, body, int64(size)),
+				orig:       body,
+			}
+			return r
+		},
+	}
+}
+
+// limitedBody is a request body that has been limited. It keeps the original
+// body so that the limit can be replaced.
+type limitedBody struct {
+	io.ReadCloser
+	orig io.ReadCloser
+}
@@ -1,6 +1,7 @@
 package router_test
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http/httptest"
 	"strings"
@@ -11,10 +12,20 @@ import (
 	"github.com/poy/go-router/pkg/router"
 )
 
+func init() {
+	// The PathPrefix keeps the Modifier from applying to the other tests'
+	// Routes.
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewLimitRequestBodyModifier(5)
+			m.PathPrefix = "/limit-request-body/"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+}
+
 func TestLimitRequestBody(t *testing.T) {
 	t.Parallel()
 
-	router.AddLimitRequestBody(5)
 	ctx := injectiontesting.WithTesting(t)
 	modifiers := injection.Resolve[injection.Group[router.Modifier]](ctx).Vals()
 	rec := httptest.NewRecorder()
@@ -35,3 +46,21 @@ func TestLimitRequestBody(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, actual)
 	}
 }
+
+func TestNewLimitRequestBodyModifier_replacesLimit(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", strings.NewReader("1234567890"))
+	req = router.NewLimitRequestBodyModifier(5).Pre(rec, req)
+	req = router.NewLimitRequestBodyModifier(8).Pre(rec, req)
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if actual, expected := string(data), "12345678"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,281 @@
+// Package metrics instruments the Router with Prometheus compatible
+// metrics. Requests are labeled by the matched Route's Path template (e.g.,
+// /widgets/{id}) rather than the requested path so that the number of
+// series stays bounded.
+//
+// Register it with AddMetrics:
+//
+//	func init() {
+//		metrics.AddMetrics(metrics.Options{})
+//	}
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// ModifierName is the Name of the metrics Modifier.
+const ModifierName = "metrics"
+
+// DefaultPath is the path the metrics are served on unless Options.Path is
+// set.
+const DefaultPath = "/metrics"
+
+// DefaultBuckets are the upper bounds (in seconds) of the latency histogram
+// unless Options.Buckets is set. They match the Prometheus client's
+// defaults.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Options configures the metrics.
+type Options struct {
+	// Path is the path the metrics are served on. It defaults to /metrics.
+	Path string
+
+	// Namespace is prefixed to each metric's name (e.g., myapp results in
+	// myapp_http_requests_total).
+	Namespace string
+
+	// Buckets are the upper bounds (in seconds) of the latency histogram.
+	// They default to DefaultBuckets.
+	Buckets []float64
+}
+
+// AddMetrics adds a Modifier that records the metrics of every request and
+// a Route that serves them in the Prometheus text exposition format. The
+// Route is excluded from the access log.
+func AddMetrics(opts Options) {
+	injection.Register[*Collector](func(ctx context.Context) *Collector {
+		return NewCollector(opts)
+	})
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			c := injection.Resolve[*Collector](ctx)
+			return injection.AddToGroup[router.Modifier](ctx, c.Modifier())
+		})
+	injection.Register[injection.Group[router.Route]](
+		func(ctx context.Context) injection.Group[router.Route] {
+			c := injection.Resolve[*Collector](ctx)
+			path := opts.Path
+			if path == "" {
+				path = DefaultPath
+			}
+			return injection.AddToGroup[router.Route](ctx, router.Route{
+				Method:           http.MethodGet,
+				Path:             path,
+				Description:      "Prometheus metrics",
+				DisableAccessLog: true,
+				Handler:          c.Handler(),
+			})
+		})
+}
+
+// Collector records the metrics of the requests served by the Router. The
+// following metrics are recorded:
+//
+//	http_requests_total            counter by method, route and status
+//	http_request_duration_seconds  histogram by method, route and status
+//	http_requests_in_flight        gauge by method and route
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu       sync.Mutex
+	requests map[requestLabels]*requestSeries
+	inFlight map[routeLabels]int64
+}
+
+type routeLabels struct {
+	method string
+	route  string
+}
+
+type requestLabels struct {
+	routeLabels
+	status int
+}
+
+type requestSeries struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// NewCollector returns a Collector. Use its Modifier and Handler to
+// instrument the Router and serve the metrics.
+func NewCollector(opts Options) *Collector {
+	buckets := append([]float64(nil), opts.Buckets...)
+	if len(buckets) == 0 {
+		buckets = append(buckets, DefaultBuckets...)
+	}
+	sort.Float64s(buckets)
+
+	return &Collector{
+		namespace: opts.Namespace,
+		buckets:   buckets,
+		requests:  map[requestLabels]*requestSeries{},
+		inFlight:  map[routeLabels]int64{},
+	}
+}
+
+// Modifier returns the Modifier that records the metrics. It runs before
+// most other Modifiers so that requests they reject are recorded.
+func (c *Collector) Modifier() router.Modifier {
+	return router.Modifier{
+		Name:     ModifierName,
+		Priority: -80,
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				route, _ := router.RouteFromContext(r.Context())
+				labels := routeLabels{method: r.Method, route: route.Path}
+
+				c.addInFlight(labels, 1)
+				defer c.addInFlight(labels, -1)
+
+				start := time.Now()
+				next.ServeHTTP(w, r)
+				latency := time.Since(start)
+
+				status := http.StatusOK
+				if rw, ok := router.FindResponseWriter(w); ok && rw.Status() != 0 {
+					status = rw.Status()
+				}
+				c.observe(requestLabels{routeLabels: labels, status: status}, latency)
+			})
+		},
+	}
+}
+
+func (c *Collector) addInFlight(labels routeLabels, delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight[labels] += delta
+}
+
+func (c *Collector) observe(labels requestLabels, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.requests[labels]
+	if !ok {
+		s = &requestSeries{buckets: make([]uint64, len(c.buckets))}
+		c.requests[labels] = s
+	}
+
+	seconds := latency.Seconds()
+	s.count++
+	s.sum += seconds
+	for i, upper := range c.buckets {
+		if seconds <= upper {
+			s.buckets[i]++
+		}
+	}
+}
+
+// Handler returns an http.Handler that serves the metrics in the Prometheus
+// text exposition format.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.WriteTo(w)
+	})
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+
+	requests := make([]requestLabels, 0, len(c.requests))
+	for labels := range c.requests {
+		requests = append(requests, labels)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].routeLabels != requests[j].routeLabels {
+			return requests[i].routeLabels.less(requests[j].routeLabels)
+		}
+		return requests[i].status < requests[j].status
+	})
+
+	name := c.name("http_requests_total")
+	fmt.Fprintf(&b, "# HELP %s Total number of HTTP requests.\n", name)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+	for _, labels := range requests {
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, labels.String(), c.requests[labels].count)
+	}
+
+	name = c.name("http_request_duration_seconds")
+	fmt.Fprintf(&b, "# HELP %s Latency of HTTP requests in seconds.\n", name)
+	fmt.Fprintf(&b, "# TYPE %s histogram\n", name)
+	for _, labels := range requests {
+		s := c.requests[labels]
+		for i, upper := range c.buckets {
+			fmt.Fprintf(&b, "%s_bucket{%s,le=%q} %d\n", name, labels.String(), formatFloat(upper), s.buckets[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels.String(), s.count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", name, labels.String(), formatFloat(s.sum))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", name, labels.String(), s.count)
+	}
+
+	inFlight := make([]routeLabels, 0, len(c.inFlight))
+	for labels := range c.inFlight {
+		inFlight = append(inFlight, labels)
+	}
+	sort.Slice(inFlight, func(i, j int) bool { return inFlight[i].less(inFlight[j]) })
+
+	name = c.name("http_requests_in_flight")
+	fmt.Fprintf(&b, "# HELP %s Number of HTTP requests being served.\n", name)
+	fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+	for _, labels := range inFlight {
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, labels.String(), c.inFlight[labels])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (c *Collector) name(name string) string {
+	if c.namespace == "" {
+		return name
+	}
+	return c.namespace + "_" + name
+}
+
+func (l routeLabels) less(o routeLabels) bool {
+	if l.route != o.route {
+		return l.route < o.route
+	}
+	return l.method < o.method
+}
+
+// String returns the labels in the exposition format.
+func (l routeLabels) String() string {
+	return fmt.Sprintf(`method="%s",route="%s"`, escapeLabelValue(l.method), escapeLabelValue(l.route))
+}
+
+// String returns the labels in the exposition format.
+func (l requestLabels) String() string {
+	return fmt.Sprintf(`%s,status="%d"`, l.routeLabels.String(), l.status)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,81 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+	"github.com/poy/go-router/pkg/router/metrics"
+
+	_ "github.com/poy/go-router/pkg/observability/cli"
+)
+
+func init() {
+	metrics.AddMetrics(metrics.Options{
+		Namespace: "test",
+		Buckets:   []float64{1, 0.5},
+	})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method: http.MethodGet,
+			Path:   "/widgets/{id}",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch router.PathVarsFromContext(r.Context())["id"] {
+				case "missing":
+					router.WriteError(w, 0, router.NotFound(errors.New("widget not found")))
+				case "panic":
+					panic("oops")
+				}
+			}),
+		})
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	for _, path := range []string{"/widgets/1", "/widgets/2", "/widgets/missing", "/widgets/panic"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code 200, got %d", rec.Code)
+	}
+	if actual := rec.Header().Get("Content-Type"); !strings.HasPrefix(actual, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected Content-Type %q", actual)
+	}
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		"# TYPE test_http_requests_total counter\n",
+		`test_http_requests_total{method="GET",route="/widgets/{id}",status="200"} 2` + "\n",
+		`test_http_requests_total{method="GET",route="/widgets/{id}",status="404"} 1` + "\n",
+		`test_http_requests_total{method="GET",route="/widgets/{id}",status="500"} 1` + "\n",
+		"# TYPE test_http_request_duration_seconds histogram\n",
+		`test_http_request_duration_seconds_bucket{method="GET",route="/widgets/{id}",status="200",le="0.5"} 2` + "\n",
+		`test_http_request_duration_seconds_bucket{method="GET",route="/widgets/{id}",status="200",le="1"} 2` + "\n",
+		`test_http_request_duration_seconds_bucket{method="GET",route="/widgets/{id}",status="200",le="+Inf"} 2` + "\n",
+		`test_http_request_duration_seconds_count{method="GET",route="/widgets/{id}",status="200"} 2` + "\n",
+		"# TYPE test_http_requests_in_flight gauge\n",
+		`test_http_requests_in_flight{method="GET",route="/metrics"} 1` + "\n",
+		`test_http_requests_in_flight{method="GET",route="/widgets/{id}"} 0` + "\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected metrics to contain %q:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(body, "/widgets/1") {
+		t.Errorf("expected the route template rather than the path:\n%s", body)
+	}
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in Modifiers. They can be referenced by a Modifier's
+// Before and After.
+const (
+	CORSModifierName             = "cors"
+	LimitRequestBodyModifierName = "limit-request-body"
+	UserInfoModifierName         = "user-info"
+	ContextModifierName          = "context"
+	RequestIDModifierName        = "request-id"
+	AccessLogModifierName        = "access-log"
+	RateLimitModifierName        = "rate-limit"
+)
+
+// sortModifiers orders the modifiers so that every Before and After
+// constraint is satisfied. Among the modifiers that are free to run, the one
+// with the lowest Priority runs first, then they are ordered by Name and
+// finally by their original position. Constraints that reference a name that
+// isn't registered are ignored. It returns an error if the constraints
+// contain a cycle.
+func sortModifiers(ms []Modifier) ([]Modifier, error) {
+	byName := map[string][]int{}
+	for i, m := range ms {
+		if m.Name != "" {
+			byName[m.Name] = append(byName[m.Name], i)
+		}
+	}
+
+	// edges[i] are the modifiers that must run after modifier i.
+	edges := make([][]int, len(ms))
+	inDegree := make([]int, len(ms))
+	addEdge := func(from, to int) {
+		edges[from] = append(edges[from], to)
+		inDegree[to]++
+	}
+	for i, m := range ms {
+		for _, name := range m.Before {
+			for _, j := range byName[name] {
+				addEdge(i, j)
+			}
+		}
+		for _, name := range m.After {
+			for _, j := range byName[name] {
+				addEdge(j, i)
+			}
+		}
+	}
+
+	less := func(i, j int) bool {
+		if ms[i].Priority != ms[j].Priority {
+			return ms[i].Priority < ms[j].Priority
+		}
+		if ms[i].Name != ms[j].Name {
+			return ms[i].Name < ms[j].Name
+		}
+		return i < j
+	}
+
+	var ready []int
+	for i := range ms {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	sorted := make([]Modifier, 0, len(ms))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool { return less(ready[a], ready[b]) })
+		i := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, ms[i])
+
+		for _, j := range edges[i] {
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if len(sorted) != len(ms) {
+		var names []string
+		for i, m := range ms {
+			if inDegree[i] > 0 {
+				names = append(names, fmt.Sprintf("%q", m.Name))
+			}
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("modifier ordering contains a cycle between %s", strings.Join(names, ", "))
+	}
+
+	return sorted, nil
+}
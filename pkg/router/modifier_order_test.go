@@ -0,0 +1,50 @@
+package router
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSortModifiers(t *testing.T) {
+	t.Parallel()
+
+	sorted, err := sortModifiers([]Modifier{
+		{Name: "auth", After: []string{"request-id"}},
+		{Name: "logging", Priority: -10},
+		{Name: "request-id", Before: []string{"logging"}},
+		{Name: "z-late", Priority: 10},
+		{Name: "b"},
+		{Name: "a"},
+		{Name: "unknown", After: []string{"does-not-exist"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, m := range sorted {
+		names = append(names, m.Name)
+	}
+	expected := []string{"a", "b", "request-id", "logging", "auth", "unknown", "z-late"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestSortModifiers_cycle(t *testing.T) {
+	t.Parallel()
+
+	_, err := sortModifiers([]Modifier{
+		{Name: "a", Before: []string{"b"}},
+		{Name: "b", Before: []string{"c"}},
+		{Name: "c", Before: []string{"a"}},
+		{Name: "d"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if actual, expected := err.Error(), `cycle between "a", "b", "c"`; !strings.Contains(actual, expected) {
+		t.Fatalf("expected %q to contain %q", actual, expected)
+	}
+}
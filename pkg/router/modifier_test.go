@@ -0,0 +1,202 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// postResults records what the Post hook saw for requests with the
+// X-Post-Test header.
+var postResults sync.Map
+
+type postResult struct {
+	status       int
+	bytesWritten int64
+	wrapped      bool
+}
+
+type wrappedKey struct{}
+
+// abortedPosts records requests with the X-Abort header that had their Post
+// invoked. It should remain empty.
+var abortedPosts sync.Map
+
+func init() {
+	for _, path := range []string{"/scoped/admin/a", "/scoped/public"} {
+		path := path
+		injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+			return injection.AddToGroup[router.Route](ctx, router.Route{
+				Path:   path,
+				Method: http.MethodGet,
+				Modifiers: []router.Modifier{
+					{
+						Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+							w.Header().Add("X-Scope", "route")
+							return r
+						},
+					},
+				},
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			})
+		})
+	}
+
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			return injection.AddToGroup[router.Modifier](ctx, router.Modifier{
+				PathPrefix: "/scoped/admin/",
+				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+					w.Header().Add("X-Scope", "admin")
+					return r
+				},
+			})
+		})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/post-hook",
+			Method: http.MethodGet,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte("hello"))
+			}),
+		})
+	})
+
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			return injection.AddToGroup[router.Modifier](ctx, router.Modifier{
+				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+					if r.Header.Get("X-Abort") == "" {
+						return r
+					}
+					return router.Abort(w, http.StatusForbidden, errors.New("aborted"))
+				},
+				Post: func(w router.ResponseWriter, r *http.Request) {
+					if key := r.Header.Get("X-Abort"); key != "" {
+						abortedPosts.Store(key, true)
+					}
+				},
+			})
+		})
+
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			return injection.AddToGroup[router.Modifier](ctx, router.Modifier{
+				Wrap: func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), wrappedKey{}, true)))
+					})
+				},
+				Post: func(w router.ResponseWriter, r *http.Request) {
+					key := r.Header.Get("X-Post-Test")
+					if key == "" {
+						return
+					}
+					wrapped, _ := r.Context().Value(wrappedKey{}).(bool)
+					postResults.Store(key, postResult{
+						status:       w.Status(),
+						bytesWritten: w.BytesWritten(),
+						wrapped:      wrapped,
+					})
+				},
+			})
+		})
+}
+
+func TestModifier_PostAndWrap(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/post-hook", nil)
+	req.Header.Set("X-Post-Test", t.Name())
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusAccepted)
+
+	v, ok := postResults.Load(t.Name())
+	if !ok {
+		t.Fatal("expected Post to be invoked")
+	}
+	if actual, expected := v.(postResult), (postResult{status: http.StatusAccepted, bytesWritten: 5, wrapped: true}); actual != expected {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestModifier_PostSeesErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/required-headers", nil)
+	req.Header.Set("X-Post-Test", t.Name())
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusBadRequest)
+
+	v, ok := postResults.Load(t.Name())
+	if !ok {
+		t.Fatal("expected Post to be invoked")
+	}
+	if actual, expected := v.(postResult).status, http.StatusBadRequest; actual != expected {
+		t.Fatalf("expected %d, got %d", expected, actual)
+	}
+}
+
+func TestModifier_Abort(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/post-hook", nil)
+	req.Header.Set("X-Abort", t.Name())
+	r.ServeHTTP(rec, req)
+
+	// The handler would have written a 202.
+	expectedStatusCode(t, rec, http.StatusForbidden)
+	expectedContentType(t, rec, "application/json")
+
+	if _, ok := abortedPosts.Load(t.Name()); ok {
+		t.Fatal("expected Post to not be invoked for an aborted request")
+	}
+}
+
+func TestModifier_Scoped(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "/scoped/admin/a", expected: []string{"admin", "route"}},
+		{path: "/scoped/public", expected: []string{"route"}},
+		{path: "/post-hook", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if actual := rec.Header().Values("X-Scope"); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.path, tc.expected, actual)
+		}
+	}
+}
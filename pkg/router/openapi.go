@@ -0,0 +1,293 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// OpenAPIInfo is the metadata that is included in the generated OpenAPI
+// document.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// AddOpenAPIRoute registers routes that serve an OpenAPI V3 document
+// describing every registered Route. The document is served as JSON on the
+// given path and as YAML on the same path with a .yaml extension (e.g.,
+// /openapi.json and /openapi.yaml).
+func AddOpenAPIRoute(path string, info OpenAPIInfo) {
+	injection.Register[openAPIConfig](func(ctx context.Context) openAPIConfig {
+		return openAPIConfig{
+			path: path,
+			info: info,
+		}
+	})
+}
+
+type openAPIConfig struct {
+	path string
+	info OpenAPIInfo
+}
+
+func (c openAPIConfig) yamlPath() string {
+	return strings.TrimSuffix(c.path, ".json") + ".yaml"
+}
+
+// routes returns the routes that serve the OpenAPI document for the given
+// routes.
+func (c openAPIConfig) routes(routes []Route) ([]Route, error) {
+	doc := buildOpenAPIDocument(c.info, routes)
+
+	jsonData, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document as JSON: %v", err)
+	}
+	yamlData, err := marshalYAML(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document as YAML: %v", err)
+	}
+
+	return []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        c.path,
+			Description: "OpenAPI document (JSON)",
+			Handler:     staticHandler("application/json", jsonData),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        c.yamlPath(),
+			Description: "OpenAPI document (YAML)",
+			Handler:     staticHandler("application/yaml", yamlData),
+		},
+	}, nil
+}
+
+func staticHandler(contentType string, data []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	})
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components *openAPIComponents                      `json:"components,omitempty"`
+}
+
+type openAPIComponents struct {
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// openAPIBearerScheme is the name of the security scheme that Routes with
+// authorization requirements refer to.
+const openAPIBearerScheme = "bearerAuth"
+
+type openAPIOperation struct {
+	Description string                     `json:"description,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   *jsonSchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+func buildOpenAPIDocument(info OpenAPIInfo, routes []Route) openAPIDocument {
+	doc := openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   map[string]map[string]*openAPIOperation{},
+	}
+
+	for _, r := range routes {
+		path, params := parsePathTemplate(r.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[path][strings.ToLower(r.Method)] = buildOpenAPIOperation(r, params)
+
+		if r.requiresAuthorization() && doc.Components == nil {
+			doc.Components = &openAPIComponents{
+				SecuritySchemes: map[string]openAPISecurityScheme{
+					openAPIBearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			}
+		}
+	}
+
+	return doc
+}
+
+func buildOpenAPIOperation(r Route, params []openAPIParameter) *openAPIOperation {
+	op := &openAPIOperation{
+		Description: r.Description,
+		Parameters:  params,
+		Responses:   map[string]openAPIResponse{},
+	}
+
+	headers := make([]string, 0, len(r.RequiredHeaders))
+	for name := range r.RequiredHeaders {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+	for _, name := range headers {
+		s := &jsonSchema{Type: "string"}
+		if value := r.RequiredHeaders[name]; value != "" && !strings.ContainsAny(value, "*?[") {
+			s.Enum = []any{value}
+		}
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:     http.CanonicalHeaderKey(name),
+			In:       "header",
+			Required: true,
+			Schema:   s,
+		})
+	}
+
+	requestSchema, responseSchema := r.RequestSchema, r.ResponseSchema
+	if p, ok := r.Handler.(schemaProvider); ok {
+		if requestSchema == nil && methodHasBody(r.Method) {
+			requestSchema = p.requestSchema()
+		}
+		if responseSchema == nil {
+			responseSchema = p.responseSchema()
+		}
+	}
+
+	if s := schemaOf(requestSchema); s != nil {
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: s},
+			},
+		}
+		op.Responses[fmt.Sprint(http.StatusBadRequest)] = openAPIResponse{
+			Description: "The request body is malformed",
+		}
+		op.Responses[fmt.Sprint(http.StatusUnprocessableEntity)] = openAPIResponse{
+			Description: "The request body failed validation",
+		}
+	}
+
+	resp := openAPIResponse{Description: http.StatusText(http.StatusOK)}
+	if s := schemaOf(responseSchema); s != nil {
+		resp.Content = map[string]openAPIMediaType{
+			"application/json": {Schema: s},
+		}
+	}
+	op.Responses[fmt.Sprint(http.StatusOK)] = resp
+
+	if r.requiresAuthorization() {
+		// OpenAPI 3.1 allows roles to be listed for non-OAuth2 schemes.
+		required := []string{}
+		required = append(required, r.RequiredScopes...)
+		required = append(required, r.RequiredRoles...)
+		required = append(required, r.RequiredGroups...)
+		op.Security = []map[string][]string{{openAPIBearerScheme: required}}
+		op.Responses[fmt.Sprint(http.StatusUnauthorized)] = openAPIResponse{
+			Description: "The request is not authenticated",
+		}
+		op.Responses[fmt.Sprint(http.StatusForbidden)] = openAPIResponse{
+			Description: "The request is not permitted",
+		}
+	}
+
+	return op
+}
+
+func methodHasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// parsePathTemplate converts a gorilla/mux path template into an OpenAPI
+// path and its path parameters. Variables with a pattern (e.g., {id:[0-9]+})
+// have the pattern moved into the parameter's schema.
+func parsePathTemplate(tpl string) (string, []openAPIParameter) {
+	var (
+		path   strings.Builder
+		params []openAPIParameter
+	)
+
+	for i := 0; i < len(tpl); i++ {
+		if tpl[i] != '{' {
+			path.WriteByte(tpl[i])
+			continue
+		}
+
+		// Find the matching closing brace. Patterns may contain braces of
+		// their own (e.g., {id:[0-9]{3}}).
+		depth, end := 0, -1
+		for j := i; j < len(tpl) && end < 0; j++ {
+			switch tpl[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			// Unbalanced braces. Leave the rest of the template alone.
+			path.WriteString(tpl[i:])
+			break
+		}
+
+		name, pattern, _ := strings.Cut(tpl[i+1:end], ":")
+		s := &jsonSchema{Type: "string"}
+		if pattern != "" {
+			s.Pattern = "^" + pattern + "$"
+		}
+		params = append(params, openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   s,
+		})
+		path.WriteString("{" + name + "}")
+		i = end
+	}
+
+	return path.String(), params
+}
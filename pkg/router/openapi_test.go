@@ -0,0 +1,137 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+type openAPIWidget struct {
+	ID       string            `json:"id"`
+	Count    int               `json:"count,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Children []openAPIWidget   `json:"children"`
+	Ignored  string            `json:"-"`
+}
+
+func init() {
+	router.AddOpenAPIRoute("/openapi.json", router.OpenAPIInfo{
+		Title:   "test",
+		Version: "v1",
+	})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:            "/widgets/{id}/parts/{part:[0-9]+}",
+			Method:          http.MethodGet,
+			Description:     "Get a widget part",
+			RequiredHeaders: map[string]string{"x-api-version": "2"},
+			ResponseSchema:  openAPIWidget{},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+		})
+	})
+}
+
+func TestOpenAPI_JSON(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	expectedStatusCode(t, rec, http.StatusOK)
+	expectedContentType(t, rec, "application/json")
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			Description string `json:"description"`
+			Parameters  []struct {
+				Name     string         `json:"name"`
+				In       string         `json:"in"`
+				Required bool           `json:"required"`
+				Schema   map[string]any `json:"schema"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := doc.OpenAPI, "3.1.0"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+
+	op, ok := doc.Paths["/widgets/{id}/parts/{part}"]["get"]
+	if !ok {
+		t.Fatalf("expected path to be documented: %s", rec.Body.String())
+	}
+	if actual, expected := op.Description, "Get a widget part"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+
+	var params []string
+	for _, p := range op.Parameters {
+		if !p.Required {
+			t.Fatalf("expected parameter %s to be required", p.Name)
+		}
+		params = append(params, p.In+":"+p.Name)
+	}
+	if expected := []string{"path:id", "path:part", "header:X-Api-Version"}; !reflect.DeepEqual(params, expected) {
+		t.Fatalf("expected %v, got %v", expected, params)
+	}
+	if actual, expected := op.Parameters[1].Schema["pattern"], "^[0-9]+$"; actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	schema := op.Responses["200"].Content["application/json"].Schema
+	props, _ := schema["properties"].(map[string]any)
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	if len(names) != 5 {
+		t.Fatalf("expected 5 properties, got %v", names)
+	}
+	if actual, expected := props["tags"], map[string]any{"type": "array", "items": map[string]any{"type": "string"}}; !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestOpenAPI_YAML(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	expectedStatusCode(t, rec, http.StatusOK)
+	expectedContentType(t, rec, "application/yaml")
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		`openapi: "3.1.0"`,
+		`  "/widgets/{id}/parts/{part}":`,
+		`        - in: "path"`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("expected YAML to contain %q:\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package router
+
+import "context"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject is the sub claim.
+	Subject string
+
+	// Email is the email claim.
+	Email string
+
+	// Issuer is the iss claim.
+	Issuer string
+
+	// Groups is the groups claim.
+	Groups []string
+
+	// Roles is the roles claim.
+	Roles []string
+
+	// Scopes is the space separated scope claim (or the scp claim used by
+	// some providers).
+	Scopes []string
+
+	// Claims are all the verified claims, including the ones above.
+	Claims Claims
+}
+
+// NewPrincipal returns the Principal of the given claims.
+func NewPrincipal(claims Claims) *Principal {
+	scopes := claims.Strings("scope")
+	if len(scopes) == 0 {
+		scopes = claims.Strings("scp")
+	}
+
+	return &Principal{
+		Subject: claims.String("sub"),
+		Email:   claims.String("email"),
+		Issuer:  claims.String("iss"),
+		Groups:  claims.Strings("groups"),
+		Roles:   claims.Strings("roles"),
+		Scopes:  scopes,
+		Claims:  claims,
+	}
+}
+
+// HasScope returns true if the Principal was granted the scope.
+func (p *Principal) HasScope(scope string) bool {
+	return containsString(p.Scopes, scope)
+}
+
+// InGroup returns true if the Principal is a member of the group.
+func (p *Principal) InGroup(group string) bool {
+	return containsString(p.Groups, group)
+}
+
+// HasRole returns true if the Principal has the role. Groups are not
+// treated as roles (see InGroup).
+func (p *Principal) HasRole(role string) bool {
+	return containsString(p.Roles, role)
+}
+
+// EmailVerified returns true if the email_verified claim is true.
+func (p *Principal) EmailVerified() bool {
+	return p.Claims.Bool("email_verified")
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the Principal of an authenticated request.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// WithPrincipal returns a new context with the Principal set.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
@@ -0,0 +1,96 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/poy/go-router/pkg/router"
+)
+
+func TestNewPrincipal(t *testing.T) {
+	t.Parallel()
+
+	var claims router.Claims
+	if err := json.Unmarshal([]byte(`{
+		"sub": "some-user",
+		"email": "user@example.com",
+		"email_verified": true,
+		"iss": "https://issuer.example.com",
+		"groups": ["admins", "devs"],
+		"roles": ["owner"],
+		"scope": "widgets:read widgets:write"
+	}`), &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	p := router.NewPrincipal(claims)
+	if actual, expected := p.Subject, "some-user"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := p.Email, "user@example.com"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := p.Issuer, "https://issuer.example.com"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := p.Groups, []string{"admins", "devs"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if actual, expected := p.Scopes, []string{"widgets:read", "widgets:write"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if !p.EmailVerified() {
+		t.Error("expected the email to be verified")
+	}
+	if !p.HasScope("widgets:write") || p.HasScope("widgets:delete") {
+		t.Errorf("unexpected scopes: %v", p.Scopes)
+	}
+	if !p.InGroup("admins") || p.InGroup("users") {
+		t.Errorf("unexpected groups: %v", p.Groups)
+	}
+	if !p.HasRole("owner") || p.HasRole("devs") || p.HasRole("users") {
+		t.Errorf("unexpected roles: %v", p.Roles)
+	}
+}
+
+func TestNewPrincipal_scp(t *testing.T) {
+	t.Parallel()
+
+	p := router.NewPrincipal(router.Claims{"scp": []any{"a", "b"}})
+	if actual, expected := p.Scopes, []string{"a", "b"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := router.PrincipalFromContext(context.Background()); ok {
+		t.Fatal("expected no principal")
+	}
+	if actual := router.GetUserID(context.Background()); actual != "" {
+		t.Fatalf("expected no user ID, got %q", actual)
+	}
+
+	ctx := router.WithPrincipal(context.Background(), router.NewPrincipal(router.Claims{
+		"sub":   "some-user",
+		"email": "user@example.com",
+	}))
+	p, ok := router.PrincipalFromContext(ctx)
+	if !ok {
+		t.Fatal("expected a principal")
+	}
+	if actual, expected := p.Email, "user@example.com"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := router.GetUserID(ctx), "some-user"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	ctx = router.WithUserID(ctx, "other-user")
+	if p, _ := router.PrincipalFromContext(ctx); p.Subject != "other-user" || p.Email != "" {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+}
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// ProblemDetailsOptions configures how errors are written as RFC 9457
+// problem details.
+type ProblemDetailsOptions struct {
+	// TypeBaseURI is prefixed to an HTTPError's Code to build the problem's
+	// type (e.g., https://example.com/problems/ + not_found). If empty, or
+	// if the error doesn't have a Code, the type is about:blank.
+	TypeBaseURI string
+}
+
+// AddProblemDetails configures the Router to write every error (including
+// not found and method not allowed errors) as RFC 9457
+// application/problem+json. An HTTPError's Code and Details, and the fields
+// of ValidationErrors, are written as extension members.
+func AddProblemDetails(opts ProblemDetailsOptions) {
+	injection.Register[*ProblemDetailsOptions](func(ctx context.Context) *ProblemDetailsOptions {
+		return &opts
+	})
+}
+
+// errorResponse is the information WriteError writes to the client.
+type errorResponse struct {
+	status  int
+	message string
+	code    string
+	details map[string]any
+	fields  ValidationErrors
+
+	// requestID is the ID assigned by the request ID Modifier, if any.
+	requestID string
+}
+
+// body returns the default representation of the error.
+func (e errorResponse) body() map[string]any {
+	body := map[string]any{
+		"error": e.message,
+	}
+	if e.code != "" {
+		body["code"] = e.code
+	}
+	if len(e.details) > 0 {
+		body["details"] = e.details
+	}
+	if len(e.fields) > 0 {
+		body["fields"] = e.fields
+	}
+	if e.requestID != "" {
+		body["request_id"] = e.requestID
+	}
+	return body
+}
+
+// problem returns the RFC 9457 representation of the error.
+func (e errorResponse) problem(opts *ProblemDetailsOptions, req *http.Request) map[string]any {
+	body := make(map[string]any, len(e.details)+8)
+
+	// Details are written first so that they can't replace the members
+	// defined by the RFC.
+	for k, v := range e.details {
+		body[k] = v
+	}
+
+	body["type"] = "about:blank"
+	if opts.TypeBaseURI != "" && e.code != "" {
+		body["type"] = opts.TypeBaseURI + e.code
+	}
+	body["title"] = http.StatusText(e.status)
+	body["status"] = e.status
+	body["detail"] = e.message
+	if req != nil && req.URL != nil {
+		body["instance"] = req.URL.RequestURI()
+	}
+	if e.code != "" {
+		body["code"] = e.code
+	}
+	if len(e.fields) > 0 {
+		body["fields"] = e.fields
+	}
+	if e.requestID != "" {
+		body["request_id"] = e.requestID
+	}
+	return body
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// NOTE: These tests are within the router package as registering
+// AddProblemDetails would change the errors written by every other test.
+
+func TestWriteError_ProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/widgets/123?x=y", nil)
+	w := newResponseWriter(rec, req, nil, &ProblemDetailsOptions{
+		TypeBaseURI: "https://example.com/problems/",
+	})
+
+	WriteError(w, 0, NotFound(errors.New("widget not found")).WithDetail("id", "123").WithDetail("status", "ignored"))
+
+	if actual, expected := rec.Code, http.StatusNotFound; actual != expected {
+		t.Fatalf("expected %d, got %d", expected, actual)
+	}
+	if actual, expected := rec.Header().Get("Content-Type"), "application/problem+json"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"type":     "https://example.com/problems/not_found",
+		"title":    "Not Found",
+		"status":   float64(http.StatusNotFound),
+		"detail":   "widget not found",
+		"instance": "/widgets/123?x=y",
+		"code":     "not_found",
+		"id":       "123",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %v, got %v", expected, m)
+	}
+}
+
+func TestWriteError_ProblemDetails_plainError(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := newResponseWriter(rec, req, nil, &ProblemDetailsOptions{})
+
+	WriteError(w, http.StatusConflict, errors.New("already exists"))
+
+	var m map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"type":     "about:blank",
+		"title":    "Conflict",
+		"status":   float64(http.StatusConflict),
+		"detail":   "already exists",
+		"instance": "/",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %v, got %v", expected, m)
+	}
+}
+
+func TestWriteError_ProblemDetails_requestID(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := newResponseWriter(rec, req, nil, &ProblemDetailsOptions{})
+	w.requestID = "some-id"
+
+	WriteError(w, http.StatusConflict, errors.New("already exists"))
+
+	var m map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := m["request_id"], "some-id"; actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
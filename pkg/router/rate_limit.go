@@ -0,0 +1,287 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/observability"
+)
+
+// RateLimit is a token bucket limit: Requests are allowed Per the given
+// duration, with bursts of up to Burst requests.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+
+	// Burst is the size of the bucket. It defaults to Requests.
+	Burst int
+}
+
+// validate returns an error if the limit would never allow a request.
+func (l RateLimit) validate() error {
+	if l.Requests <= 0 || l.Per <= 0 {
+		return fmt.Errorf("invalid rate limit: %d per %v", l.Requests, l.Per)
+	}
+	return nil
+}
+
+func (l RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// RateLimitResult is the outcome of taking a token.
+type RateLimitResult struct {
+	// Allowed is true if a token was taken.
+	Allowed bool
+
+	// Remaining is the number of tokens left in the bucket.
+	Remaining int
+
+	// Reset is how long it takes for the bucket to be full again.
+	Reset time.Duration
+
+	// RetryAfter is how long it takes for the next token to be available
+	// if the request was not allowed.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore stores the buckets of the rate limit Modifier. Implement it
+// to share the limits between replicas (e.g., with Redis).
+type RateLimitStore interface {
+	// Take takes a token from the bucket of the key, creating a full
+	// bucket if it doesn't exist yet.
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitOptions configures the rate limit Modifier.
+type RateLimitOptions struct {
+	// Limit is applied to each key.
+	Limit RateLimit
+
+	// Key returns the key that the request is limited by (e.g.,
+	// KeyByUserID). Requests with an empty key are not limited. It defaults
+	// to KeyByIP.
+	Key func(*http.Request) string
+
+	// Store stores the buckets. It defaults to a new MemoryRateLimitStore.
+	// Modifiers that share a Store should use keys that don't collide.
+	Store RateLimitStore
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// AfterAuthentication runs the Modifier after the authentication
+	// Modifiers so that Key can use the authenticated user (e.g.,
+	// KeyByUserID). Requests they reject (e.g., with invalid credentials)
+	// are then not limited. By default it runs before them so that
+	// brute-force attempts are limited too.
+	AfterAuthentication bool
+}
+
+// AddRateLimit adds a modifier that rate limits every request (see
+// NewRateLimitModifier). The Router fails to start if the limit is invalid.
+func AddRateLimit(opts RateLimitOptions) {
+	injection.Register[injection.Group[Modifier]](
+		func(ctx context.Context) injection.Group[Modifier] {
+			if err := opts.Limit.validate(); err != nil {
+				observability.FromContext(ctx).Fatalf("%v", err)
+			}
+			return injection.AddToGroup[Modifier](ctx, NewRateLimitModifier(opts))
+		})
+}
+
+// NewRateLimitModifier returns a modifier that limits the rate of requests
+// per key. Requests over the limit are rejected with a 429 and a
+// Retry-After header. Every limited response has the RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers.
+//
+// The Modifier runs before the authentication Modifiers unless
+// AfterAuthentication is set. OPTIONS requests are not limited so that CORS
+// preflight requests succeed. If the Store fails, the request is allowed.
+//
+// It panics if the limit is invalid (i.e., Requests or Per aren't positive)
+// so that a misconfigured limit doesn't disable the Modifier.
+func NewRateLimitModifier(opts RateLimitOptions) Modifier {
+	if err := opts.Limit.validate(); err != nil {
+		panic(err)
+	}
+
+	key := opts.Key
+	if key == nil {
+		key = KeyByIP
+	}
+	store := opts.Store
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	limitStr := strconv.Itoa(opts.Limit.burst())
+	policy := fmt.Sprintf("%d;w=%d", opts.Limit.Requests, ceilSeconds(opts.Limit.Per))
+	if opts.Limit.Burst > 0 && opts.Limit.Burst != opts.Limit.Requests {
+		policy += ";burst=" + limitStr
+	}
+
+	authModifiers := []string{AuthenticationModifierName, UserInfoModifierName}
+	m := Modifier{
+		Name:   RateLimitModifierName,
+		Before: authModifiers,
+	}
+	if opts.AfterAuthentication {
+		m.Before, m.After = nil, authModifiers
+	}
+	m.Pre = func(w http.ResponseWriter, r *http.Request) *http.Request {
+		if r.Method == http.MethodOptions {
+			return r
+		}
+		k := key(r)
+		if k == "" {
+			return r
+		}
+
+		res, err := store.Take(r.Context(), k, opts.Limit, now())
+		if err != nil {
+			loggerFor(w).Errorf("failed to check rate limit: %v", err)
+			return r
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", limitStr)
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		h.Set("RateLimit-Policy", policy)
+		if res.Allowed {
+			return r
+		}
+
+		h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+		return Abort(w, 0, NewHTTPError(http.StatusTooManyRequests, errRateLimited))
+	}
+	return m
+}
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// KeyByIP keys requests by the client's IP address (from
+// http.Request.RemoteAddr). Behind a proxy, use a Key that reads the
+// address from a header the proxy sets instead.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// KeyByUserID keys requests by their user ID (see GetUserID).
+// Unauthenticated requests are keyed by IP (see KeyByIP). It requires
+// RateLimitOptions.AfterAuthentication to be set.
+func KeyByUserID(r *http.Request) string {
+	if id := GetUserID(r.Context()); id != "" {
+		return "user:" + id
+	}
+	return KeyByIP(r)
+}
+
+// KeyByHeader returns a Key that keys requests by the value of the header
+// (e.g., X-Api-Key). Requests without the header are not limited.
+func KeyByHeader(name string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return "header:" + name + ":" + v
+		}
+		return ""
+	}
+}
+
+// MemoryRateLimitStore is a RateLimitStore that keeps the buckets in memory.
+// Buckets that are full again are removed periodically.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+var _ RateLimitStore = (*MemoryRateLimitStore)(nil)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// NewMemoryRateLimitStore returns an empty MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	if err := limit.validate(); err != nil {
+		return RateLimitResult{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now, limit.Per)
+
+	burst := float64(limit.burst())
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+float64(elapsed)/float64(perToken))
+		b.last = now
+	}
+
+	var res RateLimitResult
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration((burst - b.tokens) * float64(perToken))
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep removes the buckets that are full again. It runs at most once per
+// the given interval.
+func (s *MemoryRateLimitStore) sweep(now time.Time, interval time.Duration) {
+	if now.Sub(s.lastSweep) < interval {
+		return
+	}
+	s.lastSweep = now
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// testClock is a clock that only moves when advanced.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+var rateLimitClock = &testClock{now: time.Unix(1700000000, 0)}
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method: http.MethodGet,
+			Path:   "/rate-limited",
+			Modifiers: []router.Modifier{
+				router.NewRateLimitModifier(router.RateLimitOptions{
+					Limit: router.RateLimit{Requests: 2, Per: time.Minute},
+					Key:   router.KeyByHeader("X-Api-Key"),
+					Now:   rateLimitClock.Now,
+				}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+}
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method:  http.MethodGet,
+			Path:    "/rate-limited-auth",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+
+	// The Modifiers are registered (rather than set on the Route) so that
+	// they are ordered.
+	for _, m := range []router.Modifier{
+		router.NewAuthenticationModifier(router.AuthenticationOptions{
+			Authenticators: []router.Authenticator{headerAuthenticator{}},
+			Optional:       true,
+		}),
+		router.NewRateLimitModifier(router.RateLimitOptions{
+			Limit: router.RateLimit{Requests: 1, Per: time.Minute},
+			Key:   router.KeyByHeader("X-Brute-Force"),
+		}),
+	} {
+		m := m
+		m.PathPrefix = "/rate-limited-auth"
+		injection.Register[injection.Group[router.Modifier]](func(ctx context.Context) injection.Group[router.Modifier] {
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+	}
+}
+
+func TestRateLimit_beforeAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	// Requests with invalid credentials are limited too.
+	for _, expected := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/rate-limited-auth", nil)
+		req.Header.Set("X-Brute-Force", "attacker")
+		req.Header.Set("X-Test-Claims", "invalid")
+		r.ServeHTTP(rec, req)
+		expectedStatusCode(t, rec, expected)
+	}
+}
+
+func TestNewRateLimitModifier_afterAuthentication(t *testing.T) {
+	t.Parallel()
+
+	m := router.NewRateLimitModifier(router.RateLimitOptions{
+		Limit:               router.RateLimit{Requests: 1, Per: time.Minute},
+		Key:                 router.KeyByUserID,
+		AfterAuthentication: true,
+	})
+	if len(m.Before) != 0 || len(m.After) == 0 || m.After[0] != router.AuthenticationModifierName {
+		t.Fatalf("expected the Modifier to run after authentication: %v %v", m.Before, m.After)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	serve := func(apiKey string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/rate-limited", nil)
+		if apiKey != "" {
+			req.Header.Set("X-Api-Key", apiKey)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+	expectHeaders := func(rec *httptest.ResponseRecorder, expected map[string]string) {
+		t.Helper()
+		for k, v := range expected {
+			if actual := rec.Header().Get(k); actual != v {
+				t.Errorf("expected %s to be %q, got %q", k, v, actual)
+			}
+		}
+	}
+
+	rec := serve("a")
+	expectedStatusCode(t, rec, http.StatusOK)
+	expectHeaders(rec, map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "30",
+		"RateLimit-Policy":    "2;w=60",
+	})
+
+	expectedStatusCode(t, serve("a"), http.StatusOK)
+
+	rec = serve("a")
+	expectedStatusCode(t, rec, http.StatusTooManyRequests)
+	expectedContentType(t, rec, "application/json")
+	expectHeaders(rec, map[string]string{
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "60",
+		"Retry-After":         "30",
+	})
+
+	// Other keys have their own bucket and requests without a key aren't
+	// limited.
+	expectedStatusCode(t, serve("b"), http.StatusOK)
+	for i := 0; i < 3; i++ {
+		rec = serve("")
+		expectedStatusCode(t, rec, http.StatusOK)
+		expectHeaders(rec, map[string]string{"RateLimit-Limit": ""})
+	}
+
+	rateLimitClock.Advance(30 * time.Second)
+	expectedStatusCode(t, serve("a"), http.StatusOK)
+	expectedStatusCode(t, serve("a"), http.StatusTooManyRequests)
+}
+
+func TestMemoryRateLimitStore_burst(t *testing.T) {
+	t.Parallel()
+
+	s := router.NewMemoryRateLimitStore()
+	now := time.Unix(1700000000, 0)
+	limit := router.RateLimit{Requests: 1, Per: time.Second, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		res, err := s.Take(context.Background(), "k", limit, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+	res, _ := s.Take(context.Background(), "k", limit, now)
+	if res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// Long after the bucket is full again, it only holds the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		res, _ = s.Take(context.Background(), "k", limit, now)
+	}
+	if res.Allowed {
+		t.Fatal("expected the bucket to be capped at the burst")
+	}
+
+	if _, err := s.Take(context.Background(), "k", router.RateLimit{}, now); err == nil {
+		t.Fatal("expected an error for an invalid limit")
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	if actual, expected := router.KeyByIP(req), "ip:10.0.0.1"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := router.KeyByUserID(req), "ip:10.0.0.1"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	req = req.WithContext(router.WithUserID(req.Context(), "some-user"))
+	if actual, expected := router.KeyByUserID(req), "user:some-user"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestNewRateLimitModifier_invalidLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, limit := range []router.RateLimit{
+		{},
+		{Requests: 10},
+		{Per: time.Second},
+		{Requests: -1, Per: time.Second},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %+v to be rejected", limit)
+				}
+			}()
+			router.NewRateLimitModifier(router.RateLimitOptions{Limit: limit})
+		}()
+	}
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// RecoveryOptions configures how the Router recovers from panics.
+type RecoveryOptions struct {
+	// Repanic panics again after the panic has been logged and the response
+	// written. It is intended for development, where crashing is more
+	// visible than a log entry.
+	Repanic bool
+}
+
+// AddRecovery configures how the Router recovers from panics. The Router
+// recovers from panics of every Route's Modifiers and Handler regardless;
+// without it, it never panics again.
+func AddRecovery(opts RecoveryOptions) {
+	injection.Register[*RecoveryOptions](func(ctx context.Context) *RecoveryOptions {
+		return &opts
+	})
+}
+
+// recoverPanic recovers from a panic of a Route's Modifiers. The panic and
+// its stack are logged with the request-scoped logger and, unless the
+// response has already been started, a 500 is written. http.ErrAbortHandler
+// is not recovered from as it is used to abort the response. If Repanic is
+// set, the panic (or that of the Handler, see recoverHandlerPanic) is
+// panicked again once the Modifiers have unwound.
+func recoverPanic(rw *responseWriter, opts *RecoveryOptions) {
+	v := recover()
+	if v == http.ErrAbortHandler {
+		panic(v)
+	}
+	if v != nil {
+		handlePanic(rw, v, debug.Stack())
+	} else {
+		v = rw.panicked
+	}
+
+	if v != nil && opts != nil && opts.Repanic {
+		panic(v)
+	}
+}
+
+// recoverHandlerPanic recovers from a panic of a Route's Handler (see
+// handlePanic). It is deferred by the innermost handler of the Modifier
+// chain so that the 500 is written before the Modifiers unwind. Their Wrap
+// and Post therefore see the response (e.g., for the access log and
+// metrics).
+func recoverHandlerPanic(w http.ResponseWriter) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	rw := findResponseWriter(w)
+	if v == http.ErrAbortHandler || rw == nil {
+		panic(v)
+	}
+	handlePanic(rw, v, debug.Stack())
+}
+
+// handlePanic logs the panic and the stack of where it happened and writes
+// a 500 unless the response has already been started. The panic is recorded
+// so that it can be panicked again (see RecoveryOptions.Repanic).
+func handlePanic(rw *responseWriter, v any, stack []byte) {
+	rw.panicked = v
+	loggerFor(rw).
+		WithField("stack", string(stack)).
+		Errorf("recovered from panic: %v", v)
+
+	if rw.Status() == 0 {
+		WriteError(rw, http.StatusInternalServerError, fmt.Errorf("panic: %v", v))
+	}
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// NOTE: This test is within the router package as registering AddRecovery
+// would make every other test's panics crash the test binary.
+
+func TestRecoverPanic_repanic(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	rw := newResponseWriter(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+
+	defer func() {
+		if v := recover(); v != "oops" {
+			t.Fatalf("expected the panic to be repanicked, got %v", v)
+		}
+		if actual, expected := rec.Code, http.StatusInternalServerError; actual != expected {
+			t.Fatalf("expected %d, got %d", expected, actual)
+		}
+	}()
+
+	func() {
+		defer recoverPanic(rw, &RecoveryOptions{Repanic: true})
+		panic("oops")
+	}()
+}
+
+func TestRecoverPanic_repanicHandler(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	rw := newResponseWriter(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil, nil)
+
+	var posted int
+	h := chainModifiers(
+		[]Modifier{{Post: func(w ResponseWriter, r *http.Request) { posted = w.Status() }}},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("oops") }),
+	)
+
+	defer func() {
+		if v := recover(); v != "oops" {
+			t.Fatalf("expected the panic to be repanicked, got %v", v)
+		}
+		if actual, expected := posted, http.StatusInternalServerError; actual != expected {
+			t.Fatalf("expected Post to see %d, got %d", expected, actual)
+		}
+	}()
+
+	func() {
+		defer recoverPanic(rw, &RecoveryOptions{Repanic: true})
+		h.ServeHTTP(rw, rw.req)
+	}()
+}
@@ -0,0 +1,96 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+func init() {
+	for path, h := range map[string]http.HandlerFunc{
+		"/panic": func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		},
+		"/panic/after-write": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			panic("oops")
+		},
+		"/panic/abort": func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		},
+	} {
+		route := router.Route{Method: http.MethodGet, Path: path, Handler: h}
+		injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+			return injection.AddToGroup[router.Route](ctx, route)
+		})
+	}
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method: http.MethodGet,
+			Path:   "/panic/modifier",
+			Modifiers: []router.Modifier{
+				{
+					Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+						panic("oops")
+					},
+				},
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		})
+	})
+}
+
+func TestRecovery(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	for _, path := range []string{"/panic", "/panic/modifier"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		expectedStatusCode(t, rec, http.StatusInternalServerError)
+
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if actual, expected := body["error"], http.StatusText(http.StatusInternalServerError); actual != expected {
+			t.Fatalf("%s: expected %v, got %v", path, expected, actual)
+		}
+	}
+}
+
+func TestRecovery_afterWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic/after-write", nil))
+	expectedStatusCode(t, rec, http.StatusAccepted)
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected no body, got %s", rec.Body.String())
+	}
+}
+
+func TestRecovery_abort(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler, got %v", v)
+		}
+	}()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic/abort", nil))
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// DefaultRequestIDHeader is the header the request ID is read from and
+// written to unless RequestIDOptions.Header is set.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds incoming request IDs so that clients can't flood
+// the logs.
+const maxRequestIDLength = 128
+
+// RequestIDOptions configures the request ID Modifier.
+type RequestIDOptions struct {
+	// Header is the header the request ID is read from and written to. It
+	// defaults to X-Request-Id.
+	Header string
+
+	// Generate returns a new request ID. It defaults to 16 random bytes
+	// encoded as hex.
+	Generate func() string
+}
+
+// AddRequestID adds a modifier that assigns a request ID to every request
+// (see NewRequestIDModifier).
+func AddRequestID(opts RequestIDOptions) {
+	injection.Register[injection.Group[Modifier]](
+		func(ctx context.Context) injection.Group[Modifier] {
+			return injection.AddToGroup[Modifier](ctx, NewRequestIDModifier(opts))
+		})
+}
+
+// NewRequestIDModifier returns a modifier that assigns a request ID to each
+// request. The ID is read from the request's header or, if it's missing, the
+// trace ID of a W3C traceparent header. Otherwise a new one is generated.
+//
+// The ID is written to the response's header, stored in the request context
+// (see RequestIDFromContext), added as the request_id field of the
+// request-scoped logger (see observability.FromContext) and included in
+// errors written by WriteError. The Modifier has a low Priority so that it
+// runs before the other Modifiers.
+func NewRequestIDModifier(opts RequestIDOptions) Modifier {
+	header := opts.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	generate := opts.Generate
+	if generate == nil {
+		generate = generateRequestID
+	}
+
+	return Modifier{
+		Name:     RequestIDModifierName,
+		Priority: -100,
+		Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+			id := r.Header.Get(header)
+			if !validRequestID(id) {
+				id = traceIDFromTraceparent(r.Header.Get("traceparent"))
+			}
+			if id == "" {
+				id = generate()
+			}
+
+			w.Header().Set(header, id)
+			if rw := findResponseWriter(w); rw != nil {
+				rw.requestID = id
+			}
+			r = withLoggerField(w, r, "request_id", id)
+			return r.WithContext(WithRequestID(r.Context(), id))
+		},
+	}
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID from the request context. It
+// returns an empty string if the request doesn't have one.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns a new context with the request ID set.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func generateRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID returns true if the incoming request ID is safe to log and
+// echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// traceIDFromTraceparent returns the trace ID of a W3C traceparent header
+// (e.g., 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01). It returns
+// an empty string if the header is invalid.
+func traceIDFromTraceparent(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ""
+	}
+	traceID := parts[1]
+	if len(traceID) != 32 || strings.Trim(traceID, "0") == "" {
+		return ""
+	}
+	if _, err := hex.DecodeString(traceID); err != nil {
+		return ""
+	}
+	return strings.ToLower(traceID)
+}
@@ -0,0 +1,177 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/observability"
+	"github.com/poy/go-router/pkg/router"
+)
+
+var requestLogger = newRecordingLogger()
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/request-id/logger",
+			Method: http.MethodGet,
+			Modifiers: []router.Modifier{
+				{
+					Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+						return r.WithContext(observability.WithLogger(r.Context(), requestLogger))
+					},
+				},
+				router.NewRequestIDModifier(router.RequestIDOptions{}),
+				router.NewAuthenticationModifier(router.AuthenticationOptions{
+					Authenticators: []router.Authenticator{headerAuthenticator{}},
+				}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				observability.FromContext(r.Context()).Infof("handled")
+			}),
+		})
+	})
+
+	requestID := router.NewRequestIDModifier(router.RequestIDOptions{
+		Generate: func() string { return "generated-id" },
+	})
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:      "/request-id",
+			Method:    http.MethodGet,
+			Modifiers: []router.Modifier{requestID},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Context-Request-Id", router.RequestIDFromContext(r.Context()))
+			}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/request-id/custom-header",
+			Method: http.MethodGet,
+			Modifiers: []router.Modifier{
+				router.NewRequestIDModifier(router.RequestIDOptions{Header: "X-Correlation-Id"}),
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				router.WriteError(w, 0, router.NotFound(errors.New("not here")))
+			}),
+		})
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	testCases := []struct {
+		name       string
+		headers    map[string]string
+		expectedID string
+	}{
+		{
+			name:       "incoming",
+			headers:    map[string]string{"X-Request-Id": "incoming-id"},
+			expectedID: "incoming-id",
+		},
+		{
+			name:       "traceparent",
+			headers:    map[string]string{"traceparent": "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+			expectedID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:       "invalid traceparent",
+			headers:    map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "invalid incoming",
+			headers:    map[string]string{"X-Request-Id": "bad\tid"},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "too long incoming",
+			headers:    map[string]string{"X-Request-Id": strings.Repeat("a", 129)},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "generated",
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/request-id", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(rec, req)
+
+		if actual := rec.Header().Get("X-Request-Id"); actual != tc.expectedID {
+			t.Errorf("%s: expected response ID %q, got %q", tc.name, tc.expectedID, actual)
+		}
+		if actual := rec.Header().Get("X-Context-Request-Id"); actual != tc.expectedID {
+			t.Errorf("%s: expected context ID %q, got %q", tc.name, tc.expectedID, actual)
+		}
+	}
+}
+
+func TestRequestID_error(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/request-id/custom-header", nil)
+	req.Header.Set("X-Correlation-Id", "some-id")
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusNotFound)
+
+	if actual, expected := rec.Header().Get("X-Correlation-Id"), "some-id"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := body["request_id"], "some-id"; actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestRequestID_logger(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/request-id/logger", nil)
+	req.Header.Set("X-Request-Id", "some-id")
+	req.Header.Set("X-Test-Claims", `{"sub": "some-user"}`)
+	r.ServeHTTP(rec, req)
+	expectedStatusCode(t, rec, http.StatusOK)
+
+	entries := requestLogger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", entries)
+	}
+	for k, expected := range map[string]string{
+		"request_id": "some-id",
+		"user_id":    "some-user",
+	} {
+		if actual := entries[0].fields[k]; actual != expected {
+			t.Errorf("expected %s to be %q, got %q", k, expected, actual)
+		}
+	}
+}
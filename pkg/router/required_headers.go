@@ -0,0 +1,58 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// checkRequiredHeaders returns an error describing every header in required
+// that is either missing from h or has an unexpected value. An empty
+// required value only requires the header to be present. Otherwise the value
+// must either match exactly or match it as a pattern (e.g., application/*).
+// Any parameters on the header (e.g., "; charset=utf-8") are ignored when
+// matching.
+func checkRequiredHeaders(h http.Header, required map[string]string) error {
+	var missing, invalid []string
+	for name, expected := range required {
+		name = http.CanonicalHeaderKey(name)
+		actual := h.Get(name)
+		switch {
+		case actual == "":
+			missing = append(missing, name)
+		case !headerValueMatches(expected, actual):
+			invalid = append(invalid, fmt.Sprintf("%s (expected %q)", name, expected))
+		}
+	}
+	if len(missing) == 0 && len(invalid) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+	sort.Strings(invalid)
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required headers: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid header values: "+strings.Join(invalid, ", "))
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+func headerValueMatches(expected, actual string) bool {
+	if expected == "" || expected == actual {
+		return true
+	}
+
+	value, _, _ := strings.Cut(actual, ";")
+	value = strings.TrimSpace(value)
+	if value == expected {
+		return true
+	}
+	matched, err := path.Match(expected, value)
+	return err == nil && matched
+}
@@ -0,0 +1,95 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:   "/required-headers",
+			Method: http.MethodPost,
+			RequiredHeaders: map[string]string{
+				"Content-Type":  "application/*",
+				"X-Api-Version": "2",
+				"X-Tenant":      "",
+			},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}),
+		})
+	})
+}
+
+func TestRequiredHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		headers       map[string]string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name: "all present",
+			headers: map[string]string{
+				"Content-Type":  "application/json; charset=utf-8",
+				"X-Api-Version": "2",
+				"X-Tenant":      "some-tenant",
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:          "all missing",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "missing required headers: Content-Type, X-Api-Version, X-Tenant",
+		},
+		{
+			name: "invalid values",
+			headers: map[string]string{
+				"Content-Type":  "text/plain",
+				"X-Api-Version": "1",
+			},
+			expectedCode:  http.StatusBadRequest,
+			expectedError: `missing required headers: X-Tenant; invalid header values: Content-Type (expected "application/*"), X-Api-Version (expected "2")`,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Avoid issues with closure.
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := injectiontesting.WithTesting(t)
+			r := injection.Resolve[router.Router](ctx)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/required-headers", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(rec, req)
+			expectedStatusCode(t, rec, tc.expectedCode)
+
+			if tc.expectedError == "" {
+				return
+			}
+
+			var m map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			if actual, expected := m["error"], tc.expectedError; actual != expected {
+				t.Fatalf("expected %q, got %q", expected, actual)
+			}
+		})
+	}
+}
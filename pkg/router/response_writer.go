@@ -0,0 +1,179 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/poy/go-router/pkg/observability"
+)
+
+// ResponseWriter is the http.ResponseWriter the Router hands to each
+// Modifier and Handler. It records what has been written so that a
+// Modifier's Post hook can inspect the response.
+type ResponseWriter interface {
+	http.ResponseWriter
+
+	// Status returns the status code that was written or 0 if the header has
+	// not been written yet.
+	Status() int
+
+	// BytesWritten returns the number of bytes written to the body.
+	BytesWritten() int64
+
+	// Err returns the error written with WriteError or nil if there wasn't
+	// one.
+	Err() error
+}
+
+// responseWriter wraps the http.ResponseWriter handed to each handler so
+// that helpers such as WriteError have access to the Router's state.
+type responseWriter struct {
+	http.ResponseWriter
+	req            *http.Request
+	logger         observability.Logger
+	problemDetails *ProblemDetailsOptions
+	requestID      string
+
+	// loggerFields are the fields added to logger by withLoggerField.
+	loggerFields map[string]string
+
+	status       int
+	bytesWritten int64
+	err          error
+
+	// panicked is the value of a recovered panic (see handlePanic).
+	panicked any
+}
+
+var _ ResponseWriter = (*responseWriter)(nil)
+
+func newResponseWriter(
+	w http.ResponseWriter,
+	req *http.Request,
+	logger observability.Logger,
+	problemDetails *ProblemDetailsOptions,
+) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+	return &responseWriter{
+		ResponseWriter: w,
+		req:            req,
+		logger:         logger,
+		problemDetails: problemDetails,
+	}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker so that Handlers can take over the
+// connection (e.g., for WebSockets). It returns http.ErrNotSupported if the
+// underlying http.ResponseWriter can't be hijacked.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+// Status implements ResponseWriter.
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+// BytesWritten implements ResponseWriter.
+func (w *responseWriter) BytesWritten() int64 {
+	return w.bytesWritten
+}
+
+// Err implements ResponseWriter.
+func (w *responseWriter) Err() error {
+	return w.err
+}
+
+// Unwrap returns the underlying http.ResponseWriter. It is used by
+// http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// findResponseWriter returns the Router's responseWriter from w, unwrapping
+// any http.ResponseWriter that was wrapped around it. It returns nil if w
+// was not created by the Router.
+func findResponseWriter(w http.ResponseWriter) *responseWriter {
+	for {
+		switch x := w.(type) {
+		case *responseWriter:
+			return x
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = x.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// FindResponseWriter returns the Router's ResponseWriter from w, unwrapping
+// any http.ResponseWriter that was wrapped around it (see
+// http.ResponseController). It returns false if w was not created by the
+// Router. It is intended for a Modifier's Wrap, which is handed the
+// http.ResponseWriter rather than the ResponseWriter.
+func FindResponseWriter(w http.ResponseWriter) (ResponseWriter, bool) {
+	if rw := findResponseWriter(w); rw != nil {
+		return rw, true
+	}
+	return nil, false
+}
+
+// loggerFor returns the Router's request-scoped logger for w. If w was not
+// created by the Router, the logger from observability.FromContext is used.
+func loggerFor(w http.ResponseWriter) observability.Logger {
+	if rw := findResponseWriter(w); rw != nil && rw.logger != nil {
+		return rw.logger
+	}
+	return observability.FromContext(context.Background())
+}
+
+// withLoggerField adds the field to the request-scoped logger. Both the
+// logger in the returned request's context (see observability.FromContext)
+// and the one used by the Router for w are updated.
+func withLoggerField(w http.ResponseWriter, r *http.Request, name, value string) *http.Request {
+	l := observability.FromContext(r.Context()).WithField(name, value)
+	if rw := findResponseWriter(w); rw != nil {
+		rw.logger = l
+		if rw.loggerFields == nil {
+			rw.loggerFields = make(map[string]string)
+		}
+		rw.loggerFields[name] = value
+	}
+	return r.WithContext(observability.WithLogger(r.Context(), l))
+}
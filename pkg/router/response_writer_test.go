@@ -0,0 +1,83 @@
+package router_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+var hijackErrs = make(chan error, 1)
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method: http.MethodGet,
+			Path:   "/hijack",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h, ok := w.(http.Hijacker)
+				if !ok {
+					hijackErrs <- errors.New("expected an http.Hijacker")
+					return
+				}
+				conn, buf, err := h.Hijack()
+				hijackErrs <- err
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				buf.WriteString("HTTP/1.1 200 OK\r\nConnection: close\r\n\r\nhijacked")
+				buf.Flush()
+			}),
+		})
+	})
+}
+
+func TestResponseWriter_hijack(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	s := httptest.NewServer(injection.Resolve[router.Router](ctx))
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", s.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "GET /hijack HTTP/1.1\r\nHost: example.com\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-hijackErrs; err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := string(body), "hijacked"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	// httptest.ResponseRecorder can't be hijacked.
+	s.Config.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/hijack", nil))
+
+	if err := <-hijackErrs; !errors.Is(err, http.ErrNotSupported) {
+		t.Fatalf("expected %v, got %v", http.ErrNotSupported, err)
+	}
+}
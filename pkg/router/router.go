@@ -3,11 +3,12 @@ package router
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/poy/go-dependency-injection/pkg/injection"
@@ -29,108 +30,414 @@ type Route struct {
 	Handler     http.Handler
 	Description string
 
-	// TODO(poy): It would be nice if the router could enforce this instead of
-	// just adding it to the OpenAPI V3 spec.
+	// RequiredHeaders are the headers (and their values) that are required
+	// for each request. An empty value only requires the header to be
+	// present. Otherwise the value must match exactly or as a pattern (e.g.,
+	// application/*). Requests that don't satisfy them are rejected with a
+	// 400 before the Handler is invoked.
 	RequiredHeaders map[string]string
-	ResponseSchema  any
+
+	// RequestSchema is a value of the type that is expected as the (JSON)
+	// request body. If set, the body is decoded and validated (see Validate)
+	// before the Handler is invoked. Malformed bodies are rejected with a 400
+	// and invalid ones with a 422. The Handler can still read the body. Its
+	// type is reflected into the OpenAPI V3 spec.
+	RequestSchema any
+
+	// Modifiers are applied to only this Route. They are invoked in order,
+	// after every registered Modifier. They are also applied to CORS
+	// preflight requests (i.e., OPTIONS requests whose
+	// Access-Control-Request-Method is the Route's Method).
+	Modifiers []Modifier
+
+	// ResponseSchema is a value of the type that is written as the response.
+	// Its type is reflected into the OpenAPI V3 spec. If the Handler was
+	// created with Handle, it defaults to the handler's response type.
+	ResponseSchema any
+
+	// RequiredScopes are the scopes the request's Principal must have (see
+	// AddAuthentication). Requests without a Principal are rejected with a
+	// 401 and those missing a scope with a 403 before the Handler is
+	// invoked. They are listed as the Route's security requirement in the
+	// OpenAPI V3 spec.
+	RequiredScopes []string
+
+	// RequiredRoles are the roles the request's Principal must have (see
+	// Principal.HasRole). They are enforced like RequiredScopes.
+	RequiredRoles []string
+
+	// RequiredGroups are the groups the request's Principal must be a
+	// member of (see Principal.InGroup). They are enforced like
+	// RequiredScopes.
+	RequiredGroups []string
+
+	// Authorize is invoked with the request's Principal after the
+	// RequiredScopes, RequiredRoles and RequiredGroups are checked if
+	// non-nil. Requests without a Principal are rejected with a 401. If it
+	// returns an error, the request is rejected with a 403 unless the error
+	// is an HTTPError.
+	Authorize func(*Principal, *http.Request) error
+
+	// Timeout bounds how long the Handler may run. The request's context
+	// has the corresponding deadline and a 503 is written if the Handler
+	// doesn't return in time. Anything the Handler writes afterwards is
+	// discarded. As the response is buffered, streaming Handlers shouldn't
+	// set it. It defaults to the timeout registered with AddDefaultTimeout
+	// and a negative value disables that default.
+	Timeout time.Duration
+
+	// DisableAccessLog excludes the Route from the access log (see
+	// AddAccessLog). It is useful for health checks.
+	DisableAccessLog bool
 }
 
 // Modifier is used to modify each Request/Response into the Router.
+// Modifiers are nested: each one's Pre is invoked before, and its Post after,
+// every Modifier that follows it and the Route's Handler.
+//
+// The registered Modifiers are ordered by their Before and After
+// constraints, then by Priority and finally by Name. The Router fails to
+// start if the constraints contain a cycle.
 type Modifier struct {
-	// Pre is invoked before the main ServeHTTP function if non-nil.
+	// Name identifies the Modifier so that others can be ordered relative
+	// to it. It does not have to be unique.
+	Name string
+
+	// Priority orders Modifiers that aren't constrained by Before or After.
+	// Lower values run first. It defaults to 0.
+	Priority int
+
+	// Before are the names of Modifiers that this Modifier must run before.
+	Before []string
+
+	// After are the names of Modifiers that this Modifier must run after.
+	After []string
+
+	// PathPrefix limits a registered Modifier to the Routes whose Path
+	// starts with it (e.g., /apis/admin/). It applies to every Route if
+	// empty.
+	PathPrefix string
+
+	// Pre is invoked before the main ServeHTTP function if non-nil. If it
+	// returns nil, the response is assumed to have been written (e.g., via
+	// Abort) and neither the Modifiers that follow it nor the Handler are
+	// invoked. Its own Post is not invoked either.
 	Pre func(http.ResponseWriter, *http.Request) *http.Request
+
+	// Post is invoked after the main ServeHTTP function if non-nil. It is
+	// given the request that was passed to the Handler and can inspect what
+	// was written via the ResponseWriter.
+	Post func(ResponseWriter, *http.Request)
+
+	// Wrap is used to wrap the rest of the chain (the Modifiers that follow
+	// it and the Route's Handler) if non-nil. It is invoked once per Route
+	// when the Router is created.
+	Wrap func(http.Handler) http.Handler
 }
 
 func newRouter(ctx context.Context) Router {
 	routes := injection.Resolve[injection.Group[Route]](ctx).Vals()
 	logger := injection.Resolve[observability.Logger](ctx)
 	allowedMethods := make(map[string][]string)
-	modify := setupModifiers(ctx)
+	modifiers := setupModifiers(ctx, logger)
+	problemDetails, _ := injection.TryResolve[*ProblemDetailsOptions](ctx)
+	recovery, _ := injection.TryResolve[*RecoveryOptions](ctx)
+	timeoutDefault, _ := injection.TryResolve[defaultTimeout](ctx)
+
+	if cfg, ok := injection.TryResolve[openAPIConfig](ctx); ok {
+		specRoutes, err := cfg.routes(routes)
+		if err != nil {
+			logger.Fatalf("failed to build OpenAPI document: %v", err)
+		}
+		// Copy the routes so the group's backing array isn't modified.
+		routes = append(append([]Route(nil), routes...), specRoutes...)
+	}
 
 	router := mux.NewRouter()
 
 	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w = newResponseWriter(w, r, logger, problemDetails)
 		logger.Warnf("not found: %s:%s", r.Method, r.URL.String())
-		WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", r.URL.Path))
+		WriteError(w, 0, NotFound(fmt.Errorf("path %s not found", r.URL.Path)))
 	})
 	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		w = newResponseWriter(w, r, logger, problemDetails)
+		WriteError(w, 0, NewHTTPError(http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)))
 	})
 
 	sort.Slice(routes, func(i, j int) bool {
 		return routes[i].Path > routes[j].Path
 	})
 
+	// routeModifiers are the Modifiers of each Route by Path and Method.
+	// They are applied to preflight requests for the Route.
+	routeModifiers := make(map[string]map[string][]Modifier)
+	for _, r := range routes {
+		allowedMethods[r.Path] = append(allowedMethods[r.Path], r.Method)
+		if routeModifiers[r.Path] == nil {
+			routeModifiers[r.Path] = make(map[string][]Modifier)
+		}
+		routeModifiers[r.Path][r.Method] = r.Modifiers
+	}
+	for _, methods := range allowedMethods {
+		sort.Strings(methods)
+	}
+
 	for _, r := range routes {
 		// Avoid issues with closure.
 		r := r
 
 		logger.Infof("Registering route: %s %s", r.Method, r.Path)
 
-		handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			req = modify(w, req)
-			req = req.WithContext(withPathVars(req.Context(), mux.Vars(req)))
+		handler := chainModifiers(modifiersFor(modifiers, r), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := authorize(r, req); err != nil {
+				if errors.Is(err, errUnauthenticated) {
+					w.Header().Set("WWW-Authenticate", "Bearer")
+				}
+				WriteError(w, 0, err)
+				return
+			}
+			if err := checkRequiredHeaders(req.Header, r.RequiredHeaders); err != nil {
+				WriteError(w, 0, BadRequest(err))
+				return
+			}
+			if r.RequestSchema != nil {
+				if code, err := validateRequestBody(req, r.RequestSchema); err != nil {
+					WriteError(w, 0, NewHTTPError(code, err))
+					return
+				}
+			}
+			if timeout := r.timeout(timeoutDefault); timeout > 0 {
+				serveWithTimeout(w, req, r.Handler, timeout)
+				return
+			}
 			r.Handler.ServeHTTP(w, req)
-		})
-		router.Handle(r.Path, handler).Methods(r.Method)
-		allowedMethods[r.Path] = append(allowedMethods[r.Path], r.Method)
+		}))
+		matched := matchedRoute{route: r, allowedMethods: allowedMethods[r.Path]}
+		router.Handle(r.Path, routeHandler(handler, matched, logger, problemDetails, recovery)).Methods(r.Method)
 	}
 
 	for path, methods := range allowedMethods {
 		methodsStr := strings.Join(methods, ",")
+		matched := matchedRoute{
+			route:          Route{Method: http.MethodOptions, Path: path},
+			allowedMethods: methods,
+		}
+		optionsHandler := func(ms []Modifier) http.Handler {
+			optionsRoute := Route{Method: http.MethodOptions, Path: path, Modifiers: ms}
+			handler := chainModifiers(
+				modifiersFor(modifiers, optionsRoute),
+				http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+			)
+			return routeHandler(handler, matched, logger, problemDetails, recovery)
+		}
+
+		handler := optionsHandler(nil)
+		preflightHandlers := make(map[string]http.Handler, len(methods))
+		for method, ms := range routeModifiers[path] {
+			preflightHandlers[method] = optionsHandler(ms)
+		}
 		router.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			w.Header().Set("Allow", methodsStr)
-			modify(w, req)
+			// Preflight requests apply the Modifiers of the requested Route
+			// (e.g., a Route's own CORS policy).
+			if h, ok := preflightHandlers[req.Header.Get("Access-Control-Request-Method")]; ok {
+				h.ServeHTTP(w, req)
+				return
+			}
+			handler.ServeHTTP(w, req)
 		})).Methods(http.MethodOptions)
 	}
 	return router
 }
 
-func setupModifiers(ctx context.Context) func(http.ResponseWriter, *http.Request) *http.Request {
+// routeHandler returns the handler that is registered for the matched
+// Route. It adds the path variables, the matched Route and a request-scoped
+// logger (with the method and route fields) to the request's context and
+// wraps the ResponseWriter. Panics of the Modifiers and the Handler are
+// recovered from (see AddRecovery).
+func routeHandler(
+	h http.Handler,
+	matched matchedRoute,
+	logger observability.Logger,
+	problemDetails *ProblemDetailsOptions,
+	recovery *RecoveryOptions,
+) http.Handler {
+	logger = logger.
+		WithField("method", matched.route.Method).
+		WithField("route", matched.route.Path)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := withMatchedRoute(withPathVars(req.Context(), mux.Vars(req)), matched)
+		req = req.WithContext(observability.WithLogger(ctx, logger))
+		rw := newResponseWriter(w, req, logger, problemDetails)
+		defer recoverPanic(rw, recovery)
+		h.ServeHTTP(rw, req)
+	})
+}
+
+// setupModifiers returns every registered Modifier in the order they should
+// be invoked.
+func setupModifiers(ctx context.Context, logger observability.Logger) []Modifier {
 	g, _ := injection.TryResolve[injection.Group[Modifier]](ctx)
-	ms := g.Vals()
+	ms, err := sortModifiers(g.Vals())
+	if err != nil {
+		logger.Fatalf("invalid modifiers: %v", err)
+	}
+	return ms
+}
 
-	return func(w http.ResponseWriter, r *http.Request) *http.Request {
-		for _, m := range ms {
-			if m.Pre == nil {
-				continue
-			}
-			r = m.Pre(w, r)
+// modifiersFor returns the Modifiers that apply to the given Route: the
+// registered ones whose PathPrefix matches, followed by the Route's own.
+func modifiersFor(registered []Modifier, r Route) []Modifier {
+	var ms []Modifier
+	for _, m := range registered {
+		if strings.HasPrefix(r.Path, m.PathPrefix) {
+			ms = append(ms, m)
 		}
-		return r
 	}
+	return append(ms, r.Modifiers...)
 }
 
-// WriteError writes an error to the response as JSON.
+// chainModifiers wraps the handler with the given Modifiers.
+func chainModifiers(ms []Modifier, h http.Handler) http.Handler {
+	// The innermost handler records the final request so that Post hooks
+	// see the request that was passed to the Handler. It also recovers from
+	// the Handler's panics so that the Modifiers see the resulting 500.
+	var next http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer recoverHandlerPanic(w)
+		if rw := findResponseWriter(w); rw != nil {
+			rw.req = r
+		}
+		h.ServeHTTP(w, r)
+	})
+
+	for i := len(ms) - 1; i >= 0; i-- {
+		next = wrapModifier(ms[i], next)
+	}
+	return next
+}
+
+func wrapModifier(m Modifier, next http.Handler) http.Handler {
+	if m.Wrap != nil {
+		next = m.Wrap(next)
+	}
+	if m.Pre == nil && m.Post == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.Pre != nil {
+			if r = m.Pre(w, r); r == nil {
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+		if m.Post != nil {
+			if rw := findResponseWriter(w); rw != nil {
+				m.Post(rw, rw.req)
+			}
+		}
+	})
+}
+
+// Abort writes the error (see WriteError) and returns nil. It is intended to
+// be returned from a Modifier's Pre to reject the request:
+//
+//	if !allowed {
+//		return router.Abort(w, http.StatusForbidden, err)
+//	}
+func Abort(w http.ResponseWriter, code int, err error) *http.Request {
+	WriteError(w, code, err)
+	return nil
+}
+
+// WriteError writes an error to the response as JSON. If code is 0, it is
+// derived from err (see StatusCode). If err is (or wraps) an HTTPError, its
+// Message, Code and Details are written. If err is (or wraps)
+// ValidationErrors, each invalid field is listed under "fields".
+//
+// The message of a 5xx error is only written if it is an HTTPError's
+// Message. Otherwise, the error is logged and only the status text is
+// written so that internal errors are not leaked to clients.
+//
+// By default the error is written as {"error": "..."}. See AddProblemDetails
+// for writing RFC 9457 problem details instead. The request's ID is included
+// as "request_id" if it has one (see AddRequestID).
 func WriteError(w http.ResponseWriter, code int, err error) {
+	if code == 0 {
+		code = StatusCode(err)
+	}
+
+	e := errorResponse{
+		status:  code,
+		message: err.Error(),
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		e.message = httpErr.Message
+		if e.message == "" {
+			e.message = NewHTTPError(code, httpErr.Err).Message
+		}
+		e.code = httpErr.Code
+		e.details = httpErr.Details
+	} else if code >= http.StatusInternalServerError {
+		e.message = http.StatusText(code)
+	}
+	errors.As(err, &e.fields)
+
+	if code >= http.StatusInternalServerError {
+		loggerFor(w).Errorf("responding with %d: %v", code, err)
+	}
+
+	rw := findResponseWriter(w)
+	if rw != nil {
+		e.requestID = rw.requestID
+		rw.err = err
+	}
+
+	contentType, body := "application/json", e.body()
+	if rw != nil && rw.problemDetails != nil {
+		contentType, body = "application/problem+json", e.problem(rw.problemDetails, rw.req)
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(code)
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"error": err.Error(),
-	}); err != nil {
-		log.Panicf("failed to marshal error: %v", err)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		// The header has already been written, so all that can be done is
+		// to log it.
+		loggerFor(w).Errorf("failed to write error: %v", err)
 	}
 }
 
 // ReadRequest reads a request from the body and unmarshals it into the given
-// Type.
+// Type. The result is then validated (see Validate).
 func ReadRequest[TReq any](r *http.Request) (TReq, error) {
 	defer r.Body.Close()
 	var req TReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return req, err
 	}
+	if err := Validate(req); err != nil {
+		return req, err
+	}
 	return req, nil
 }
 
-// WriteResponse writes a response to the ResponseWriter. If writing fails, it
-// will send a 500, however this is likely to not arrive to the user given
-// writing the initial response failed. Therefore, this function does not
-// return an error, as nothing actinally useful can be done with it.
+// WriteResponse writes a response to the ResponseWriter. If encoding fails, it
+// will send a 500. If writing fails after the response has been started, the
+// error is logged as nothing else can be sent to the user. Therefore, this
+// function does not return an error, as nothing actinally useful can be done
+// with it.
 func WriteResponse[TReq any](w http.ResponseWriter, data TReq) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to write response: %v", err))
+		err = fmt.Errorf("failed to write response: %v", err)
+		if rw := findResponseWriter(w); rw != nil && rw.Status() != 0 {
+			loggerFor(w).Errorf("%v", err)
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 }
@@ -148,3 +455,29 @@ func PathVarsFromContext(ctx context.Context) map[string]string {
 func withPathVars(ctx context.Context, vars map[string]string) context.Context {
 	return context.WithValue(ctx, pathVarKey{}, vars)
 }
+
+type matchedRouteKey struct{}
+
+// matchedRoute is the Route that matched the request along with every method
+// registered for its Path.
+type matchedRoute struct {
+	route          Route
+	allowedMethods []string
+}
+
+// RouteFromContext returns the Route that matched the request. Its Path is
+// the template (e.g., /widgets/{id}) rather than the requested path. It
+// returns false if the context is not from a request served by the Router.
+func RouteFromContext(ctx context.Context) (Route, bool) {
+	m, ok := ctx.Value(matchedRouteKey{}).(matchedRoute)
+	return m.route, ok
+}
+
+func allowedMethodsFromContext(ctx context.Context) []string {
+	m, _ := ctx.Value(matchedRouteKey{}).(matchedRoute)
+	return m.allowedMethods
+}
+
+func withMatchedRoute(ctx context.Context, m matchedRoute) context.Context {
+	return context.WithValue(ctx, matchedRouteKey{}, m)
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/poy/go-dependency-injection/pkg/injection"
 	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
 	"github.com/poy/go-router/pkg/router"
+
+	// Register the STDOUT logger.
+	_ "github.com/poy/go-router/pkg/observability/cli"
 )
 
 func init() {
@@ -0,0 +1,188 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+)
+
+// defaultTimeout is the Timeout of Routes that don't set their own.
+type defaultTimeout time.Duration
+
+// AddDefaultTimeout sets the Timeout of every Route that doesn't set its own
+// (see Route.Timeout).
+func AddDefaultTimeout(d time.Duration) {
+	injection.Register[defaultTimeout](func(ctx context.Context) defaultTimeout {
+		return defaultTimeout(d)
+	})
+}
+
+// timeout returns the Route's Timeout or the default if it isn't set. A
+// negative Timeout disables the default.
+func (r Route) timeout(d defaultTimeout) time.Duration {
+	switch {
+	case r.Timeout > 0:
+		return r.Timeout
+	case r.Timeout < 0:
+		return 0
+	default:
+		return time.Duration(d)
+	}
+}
+
+// errTimeout is written when a Handler doesn't finish within its Route's
+// Timeout.
+var errTimeout = errors.New("request timed out")
+
+// serveWithTimeout invokes h with a context that has the given timeout. The
+// response is buffered and only written once h returns. If h doesn't return
+// in time, a 503 is written instead and any later writes from h fail with
+// http.ErrHandlerTimeout. Panics in h are handled like those of any other
+// Handler (see recoverHandlerPanic) but with h's stack. Panics after the
+// timeout are only logged.
+func serveWithTimeout(w http.ResponseWriter, req *http.Request, h http.Handler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	tw := &timeoutWriter{header: http.Header{}}
+
+	// The Handler gets its own responseWriter so that helpers such as
+	// WriteError can't modify the Router's state after the timeout.
+	hw := &responseWriter{ResponseWriter: tw, req: req}
+	if rw := findResponseWriter(w); rw != nil {
+		hw.logger = rw.logger
+		hw.problemDetails = rw.problemDetails
+		hw.requestID = rw.requestID
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// The stack has to be captured here as it is lost once the
+			// panic is handed to the request's goroutine.
+			stack := debug.Stack()
+			if !tw.setPanic(&handlerPanic{value: v, stack: stack}) {
+				loggerFor(hw).
+					WithField("stack", string(stack)).
+					Errorf("recovered from panic after the request timed out: %v", v)
+			}
+		}()
+		h.ServeHTTP(hw, req)
+	}()
+
+	select {
+	case <-done:
+		if p := tw.panicked; p != nil {
+			p.handle(w)
+			return
+		}
+
+		header := w.Header()
+		for k, v := range tw.header {
+			header[k] = v
+		}
+		if tw.status != 0 {
+			w.WriteHeader(tw.status)
+		}
+		w.Write(tw.buf.Bytes())
+		if rw := findResponseWriter(w); rw != nil && hw.err != nil {
+			rw.err = hw.err
+		}
+	case <-ctx.Done():
+		// The Handler may have panicked just as the timeout expired.
+		if p := tw.timeout(); p != nil {
+			p.handle(w)
+			return
+		}
+		WriteError(w, 0, NewHTTPError(http.StatusServiceUnavailable, errTimeout))
+	}
+}
+
+// handlerPanic is a panic of a Handler that has a timeout along with the
+// stack of where it happened.
+type handlerPanic struct {
+	value any
+	stack []byte
+}
+
+// handle handles the panic on the request's goroutine (see handlePanic).
+// http.ErrAbortHandler is panicked again.
+func (p *handlerPanic) handle(w http.ResponseWriter) {
+	rw := findResponseWriter(w)
+	if p.value == http.ErrAbortHandler || rw == nil {
+		panic(p.value)
+	}
+	handlePanic(rw, p.value, p.stack)
+}
+
+// timeoutWriter buffers the response of a Handler that has a timeout.
+type timeoutWriter struct {
+	header http.Header
+
+	mu       sync.Mutex
+	status   int
+	buf      bytes.Buffer
+	timedOut bool
+	panicked *handlerPanic
+}
+
+// Header implements http.ResponseWriter.
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.status != 0 {
+		return
+	}
+	w.status = code
+}
+
+// Write implements http.ResponseWriter.
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(data)
+}
+
+// timeout discards any later writes. It returns the Handler's panic if it
+// panicked before the timeout.
+func (w *timeoutWriter) timeout() *handlerPanic {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+	return w.panicked
+}
+
+// setPanic records the Handler's panic. It returns false if the request has
+// already timed out and nothing will handle it.
+func (w *timeoutWriter) setPanic(p *handlerPanic) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return false
+	}
+	w.panicked = p
+	return true
+}
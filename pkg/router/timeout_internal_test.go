@@ -0,0 +1,112 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poy/go-router/pkg/observability"
+)
+
+func TestRoute_timeout(t *testing.T) {
+	t.Parallel()
+
+	d := defaultTimeout(time.Second)
+	for _, tc := range []struct {
+		timeout  time.Duration
+		expected time.Duration
+	}{
+		{timeout: 0, expected: time.Second},
+		{timeout: time.Minute, expected: time.Minute},
+		{timeout: -1, expected: 0},
+	} {
+		if actual := (Route{Timeout: tc.timeout}).timeout(d); actual != tc.expected {
+			t.Errorf("%v: expected %v, got %v", tc.timeout, tc.expected, actual)
+		}
+	}
+
+	if actual := (Route{}).timeout(0); actual != 0 {
+		t.Errorf("expected no timeout, got %v", actual)
+	}
+}
+
+// stackLogger sends each error and its stack field to errs.
+type stackLogger struct {
+	observability.Logger
+	errs  chan string
+	stack string
+}
+
+func (l stackLogger) Errorf(format string, args ...any) {
+	l.errs <- fmt.Sprintf(format, args...) + "\n" + l.stack
+}
+
+func (l stackLogger) WithField(name, value string) observability.Logger {
+	if name == "stack" {
+		l.stack = value
+	}
+	return l
+}
+
+func newTimeoutResponseWriter() (*httptest.ResponseRecorder, *responseWriter, chan string) {
+	rec := httptest.NewRecorder()
+	errs := make(chan string, 10)
+	rw := newResponseWriter(rec, httptest.NewRequest(http.MethodGet, "/", nil), stackLogger{errs: errs}, nil)
+	return rec, rw, errs
+}
+
+func panickingTimeoutHandler(w http.ResponseWriter, r *http.Request) {
+	panic("oops")
+}
+
+func TestServeWithTimeout_panic(t *testing.T) {
+	t.Parallel()
+
+	rec, rw, errs := newTimeoutResponseWriter()
+	serveWithTimeout(rw, rw.req, http.HandlerFunc(panickingTimeoutHandler), time.Minute)
+
+	if actual, expected := rec.Code, http.StatusInternalServerError; actual != expected {
+		t.Fatalf("expected %d, got %d", expected, actual)
+	}
+	if rw.panicked != "oops" {
+		t.Fatalf("expected the panic to be recorded, got %v", rw.panicked)
+	}
+	if entry := <-errs; !strings.Contains(entry, "panickingTimeoutHandler") {
+		t.Fatalf("expected the stack of the Handler, got %s", entry)
+	}
+}
+
+func TestServeWithTimeout_latePanic(t *testing.T) {
+	t.Parallel()
+
+	rec, rw, errs := newTimeoutResponseWriter()
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		// Give serveWithTimeout time to write the timeout.
+		time.Sleep(10 * time.Millisecond)
+		panickingTimeoutHandler(w, r)
+	})
+	serveWithTimeout(rw, rw.req, h, 10*time.Millisecond)
+
+	if actual, expected := rec.Code, http.StatusServiceUnavailable; actual != expected {
+		t.Fatalf("expected %d, got %d", expected, actual)
+	}
+	for {
+		select {
+		case entry := <-errs:
+			if !strings.Contains(entry, "after the request timed out: oops") {
+				// E.g., the 503.
+				continue
+			}
+			if !strings.Contains(entry, "panickingTimeoutHandler") {
+				t.Fatalf("expected the stack of the Handler, got %s", entry)
+			}
+			return
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the late panic to be logged")
+		}
+	}
+}
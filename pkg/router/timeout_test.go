@@ -0,0 +1,99 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+var lateWriteErrs = make(chan error, 1)
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method:  http.MethodGet,
+			Path:    "/timeout/slow",
+			Timeout: 10 * time.Millisecond,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				// Give the Router time to write the timeout.
+				time.Sleep(10 * time.Millisecond)
+				w.Header().Set("X-Late", "true")
+				_, err := w.Write([]byte("late"))
+				lateWriteErrs <- err
+			}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method:  http.MethodGet,
+			Path:    "/timeout/fast",
+			Timeout: time.Minute,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := r.Context().Deadline(); !ok {
+					panic("expected a deadline")
+				}
+				w.Header().Set("X-Fast", "true")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("fast"))
+			}),
+		})
+	})
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method:  http.MethodGet,
+			Path:    "/timeout/error",
+			Timeout: time.Minute,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				router.WriteError(w, 0, router.Conflict(errors.New("already exists")))
+			}),
+		})
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timeout/slow", nil))
+	expectedStatusCode(t, rec, http.StatusServiceUnavailable)
+	expectedContentType(t, rec, "application/json")
+
+	if err := <-lateWriteErrs; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Fatalf("expected the late write to fail with %v, got %v", http.ErrHandlerTimeout, err)
+	}
+	if rec.Header().Get("X-Late") != "" {
+		t.Fatal("expected the late header to be discarded")
+	}
+}
+
+func TestTimeout_inTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timeout/fast", nil))
+	expectedStatusCode(t, rec, http.StatusCreated)
+
+	if actual, expected := rec.Header().Get("X-Fast"), "true"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := rec.Body.String(), "fast"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timeout/error", nil))
+	expectedStatusCode(t, rec, http.StatusConflict)
+	expectedContentType(t, rec, "application/json")
+}
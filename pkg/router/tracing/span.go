@@ -0,0 +1,193 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the ID as lowercase hex.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid returns true if the ID is not all zeros.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the ID as lowercase hex.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid returns true if the ID is not all zeros.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// FlagsSampled is the W3C trace flag that marks a trace as sampled.
+const FlagsSampled byte = 0x01
+
+// SpanContext is the part of a span that is propagated across services.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+}
+
+// IsValid returns true if both IDs are valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled returns true if the sampled flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagsSampled != 0
+}
+
+// Traceparent returns the W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// Extract returns the span context of the W3C traceparent and tracestate
+// headers. It returns false if the traceparent header is missing or invalid.
+func Extract(h http.Header) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(h.Get("traceparent")), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	// Version 00 has exactly 4 parts. Later versions may append more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) {
+		return SpanContext{}, false
+	}
+	var flags [1]byte
+	if !decodeHex(flags[:], parts[3]) {
+		return SpanContext{}, false
+	}
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+
+	sc.TraceState = strings.Join(h.Values("tracestate"), ",")
+	return sc, true
+}
+
+// Inject sets the W3C traceparent and tracestate headers to the span
+// context from ctx (see SpanContextFromContext). It is used to propagate the
+// trace to outgoing requests. Nothing is set if ctx doesn't have one.
+func Inject(ctx context.Context, h http.Header) {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return
+	}
+	h.Set("traceparent", sc.Traceparent())
+	if sc.TraceState != "" {
+		h.Set("tracestate", sc.TraceState)
+	} else {
+		h.Del("tracestate")
+	}
+}
+
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != 2*len(dst) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+type spanContextKey struct{}
+
+// SpanContextFromContext returns the span context of the request's server
+// span.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// ContextWithSpanContext returns a new context with the span context set.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanKind describes the relationship of a span to its parent and children.
+type SpanKind string
+
+// SpanKindServer is the kind of the spans started for incoming requests.
+const SpanKindServer SpanKind = "server"
+
+// StatusCode is the status of a span.
+type StatusCode int
+
+// The status codes of a span. Following the OpenTelemetry semantic
+// conventions, a server span's status is only set to StatusError for 5xx
+// responses.
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Span is a finished server span.
+type Span struct {
+	// Name is the Route's method and path template (e.g., GET /widgets/{id}).
+	Name string
+	Kind SpanKind
+
+	SpanContext SpanContext
+
+	// Parent is the span context of the incoming request. It is not valid
+	// if the request started a new trace.
+	Parent SpanContext
+
+	Start time.Time
+	End   time.Time
+
+	// Attributes follow the OpenTelemetry HTTP semantic conventions (e.g.,
+	// http.request.method and http.route).
+	Attributes map[string]any
+
+	Status        StatusCode
+	StatusMessage string
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		mustRead(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		mustRead(id[:])
+	}
+	return id
+}
+
+func mustRead(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+}
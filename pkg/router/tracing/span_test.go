@@ -0,0 +1,80 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/poy/go-router/pkg/router/tracing"
+)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Add("tracestate", "a=1")
+	h.Add("tracestate", "b=2")
+
+	sc, ok := tracing.Extract(h)
+	if !ok {
+		t.Fatal("expected a span context")
+	}
+	if actual, expected := sc.TraceID.String(), "4bf92f3577b34da6a3ce929d0e0e4736"; actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if actual, expected := sc.SpanID.String(), "00f067aa0ba902b7"; actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if !sc.IsSampled() {
+		t.Error("expected the span context to be sampled")
+	}
+	if actual, expected := sc.TraceState, "a=1,b=2"; actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestExtract_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, traceparent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	} {
+		h := http.Header{}
+		h.Set("traceparent", traceparent)
+		if _, ok := tracing.Extract(h); ok {
+			t.Errorf("%q: expected it to be invalid", traceparent)
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	in := http.Header{}
+	in.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	in.Set("tracestate", "a=1")
+	sc, _ := tracing.Extract(in)
+
+	out := http.Header{}
+	tracing.Inject(tracing.ContextWithSpanContext(context.Background(), sc), out)
+	if actual, expected := out.Get("traceparent"), in.Get("traceparent"); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if actual, expected := out.Get("tracestate"), "a=1"; actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+
+	out = http.Header{}
+	tracing.Inject(context.Background(), out)
+	if len(out) != 0 {
+		t.Errorf("expected no headers, got %v", out)
+	}
+}
@@ -0,0 +1,158 @@
+// Package tracing starts a server span for each request served by the
+// Router. The W3C traceparent and tracestate headers of incoming requests
+// are honored and the span's context is stored in the request context (see
+// SpanContextFromContext) so that handlers can propagate it (see Inject).
+//
+// Finished spans are handed to an Exporter. InMemoryExporter keeps them in
+// memory, which is useful for tests.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	"github.com/poy/go-router/pkg/router"
+)
+
+// ModifierName is the Name of the tracing Modifier.
+const ModifierName = "tracing"
+
+// Exporter receives each finished span.
+type Exporter interface {
+	ExportSpan(Span)
+}
+
+// Options configures the tracing Modifier.
+type Options struct {
+	// Exporter receives each finished span. Spans that aren't sampled are
+	// not exported.
+	Exporter Exporter
+
+	// Sample decides whether a new trace is sampled. It is not used for
+	// requests with a traceparent as their sampled flag is honored. It
+	// defaults to sampling every trace.
+	Sample func(*http.Request) bool
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// AddTracing adds a Modifier that traces every request (see NewModifier).
+func AddTracing(opts Options) {
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			return injection.AddToGroup[router.Modifier](ctx, NewModifier(opts))
+		})
+}
+
+// NewModifier returns a Modifier that starts a server span for each request.
+// The span is named after the matched Route's method and Path template. Its
+// status is set from the response's status code and the error written with
+// router.WriteError, if any.
+//
+// The Modifier runs after the request ID Modifier and before most others so
+// that the span includes them.
+func NewModifier(opts Options) router.Modifier {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return router.Modifier{
+		Name:     ModifierName,
+		Priority: -95,
+		After:    []string{router.RequestIDModifierName},
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				parent, hasParent := Extract(r.Header)
+				sc := SpanContext{SpanID: newSpanID()}
+				if hasParent {
+					sc.TraceID = parent.TraceID
+					sc.Flags = parent.Flags
+					sc.TraceState = parent.TraceState
+				} else {
+					sc.TraceID = newTraceID()
+					if opts.Sample == nil || opts.Sample(r) {
+						sc.Flags = FlagsSampled
+					}
+				}
+
+				route, _ := router.RouteFromContext(r.Context())
+				span := Span{
+					Name:        r.Method + " " + route.Path,
+					Kind:        SpanKindServer,
+					SpanContext: sc,
+					Parent:      parent,
+					Start:       now(),
+					Attributes: map[string]any{
+						"http.request.method": r.Method,
+						"http.route":          route.Path,
+						"url.path":            r.URL.Path,
+					},
+				}
+
+				next.ServeHTTP(w, r.WithContext(ContextWithSpanContext(r.Context(), sc)))
+
+				span.End = now()
+				if !sc.IsSampled() || opts.Exporter == nil {
+					return
+				}
+
+				status := http.StatusOK
+				var err error
+				if rw, ok := router.FindResponseWriter(w); ok {
+					if rw.Status() != 0 {
+						status = rw.Status()
+					}
+					err = rw.Err()
+				}
+				span.Attributes["http.response.status_code"] = status
+				if err != nil {
+					span.Attributes["error.message"] = err.Error()
+				}
+				if status >= http.StatusInternalServerError {
+					span.Status = StatusError
+					span.StatusMessage = http.StatusText(status)
+					if err != nil {
+						span.StatusMessage = err.Error()
+					}
+				}
+
+				opts.Exporter.ExportSpan(span)
+			})
+		},
+	}
+}
+
+// InMemoryExporter is an Exporter that keeps every span in memory. The zero
+// value is ready to use.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+var _ Exporter = (*InMemoryExporter)(nil)
+
+// ExportSpan implements Exporter.
+func (e *InMemoryExporter) ExportSpan(s Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+// Spans returns the exported spans.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Span(nil), e.spans...)
+}
+
+// Reset removes every exported span.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
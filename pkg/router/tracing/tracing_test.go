@@ -0,0 +1,157 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+	"github.com/poy/go-router/pkg/router/tracing"
+
+	_ "github.com/poy/go-router/pkg/observability/cli"
+)
+
+var exporter = &tracing.InMemoryExporter{}
+
+func init() {
+	ms := []router.Modifier{
+		tracing.NewModifier(tracing.Options{
+			Exporter: exporter,
+			Sample:   func(r *http.Request) bool { return r.Header.Get("X-Drop") == "" },
+		}),
+	}
+
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Method:    http.MethodGet,
+			Path:      "/widgets/{id}",
+			Modifiers: ms,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sc, ok := tracing.SpanContextFromContext(r.Context())
+				if !ok {
+					panic("expected a span context")
+				}
+				w.Header().Set("X-Trace-Id", sc.TraceID.String())
+
+				switch router.PathVarsFromContext(r.Context())["id"] {
+				case "missing":
+					router.WriteError(w, 0, router.NotFound(errors.New("widget not found")))
+				case "broken":
+					router.WriteError(w, 0, errors.New("database is down"))
+				}
+			}),
+		})
+	})
+}
+
+func TestTracing(t *testing.T) {
+	ctx := injectiontesting.WithTesting(t)
+	r := injection.Resolve[router.Router](ctx)
+
+	serve := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		exporter.Reset()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("new trace", func(t *testing.T) {
+		rec := serve("/widgets/1", nil)
+		spans := exporter.Spans()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+		s := spans[0]
+		if actual, expected := s.Name, "GET /widgets/{id}"; actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+		if s.Kind != tracing.SpanKindServer {
+			t.Errorf("unexpected kind %s", s.Kind)
+		}
+		if s.Parent.IsValid() {
+			t.Errorf("expected no parent, got %v", s.Parent)
+		}
+		if actual, expected := s.SpanContext.TraceID.String(), rec.Header().Get("X-Trace-Id"); actual != expected {
+			t.Errorf("expected the handler to see trace %s, got %s", actual, expected)
+		}
+		if actual, expected := s.Attributes["http.route"], "/widgets/{id}"; actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+		if actual, expected := s.Attributes["http.response.status_code"], http.StatusOK; actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+		if s.Status != tracing.StatusUnset {
+			t.Errorf("unexpected status %v", s.Status)
+		}
+		if s.End.Before(s.Start) {
+			t.Errorf("expected the span to end after it started")
+		}
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		serve("/widgets/1", map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"tracestate":  "a=1",
+		})
+		spans := exporter.Spans()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+		s := spans[0]
+		if actual, expected := s.SpanContext.TraceID.String(), "4bf92f3577b34da6a3ce929d0e0e4736"; actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+		if actual, expected := s.Parent.SpanID.String(), "00f067aa0ba902b7"; actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+		if s.SpanContext.SpanID == s.Parent.SpanID {
+			t.Error("expected a new span ID")
+		}
+		if actual, expected := s.SpanContext.TraceState, "a=1"; actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		serve("/widgets/1", map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		})
+		serve("/widgets/1", map[string]string{"X-Drop": "true"})
+		if spans := exporter.Spans(); len(spans) != 0 {
+			t.Fatalf("expected no spans, got %v", spans)
+		}
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		serve("/widgets/missing", nil)
+		s := exporter.Spans()[0]
+		if actual, expected := s.Attributes["http.response.status_code"], http.StatusNotFound; actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+		if actual, expected := s.Attributes["error.message"], "widget not found"; actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+		if s.Status != tracing.StatusUnset {
+			t.Errorf("unexpected status %v", s.Status)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		serve("/widgets/broken", nil)
+		s := exporter.Spans()[0]
+		if s.Status != tracing.StatusError {
+			t.Errorf("unexpected status %v", s.Status)
+		}
+		if actual, expected := s.StatusMessage, "database is down"; actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	})
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/poy/go-dependency-injection/pkg/injection"
-	"github.com/poy/go-router/pkg/observability"
 )
 
 // AddUserInfoModifier adds a user info modifier to the router. This modifier
-// updates the request context with the user info from the request.
+// updates the request context with the user info from the request. Invalid
+// user info is logged and otherwise ignored. See AddAuthentication with an
+// APIGatewayAuthenticator to reject such requests instead.
 // //
 // NOTE: This currently is tied directly to the GCP API Gateway. It assumes
 // there is a JWT on the X-Apigateway-Api-Userinfo header. See
@@ -21,44 +23,78 @@ import (
 func AddUserInfoModifier() {
 	injection.Register[injection.Group[Modifier]](
 		func(ctx context.Context) injection.Group[Modifier] {
-			logger := injection.Resolve[observability.Logger](ctx)
+			return injection.AddToGroup[Modifier](ctx, NewUserInfoModifier())
+		})
+}
 
-			return injection.AddToGroup[Modifier](ctx, Modifier{
-				Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
-					if r.Method == http.MethodOptions {
-						return r
-					}
+// NewUserInfoModifier returns the user info modifier (see
+// AddUserInfoModifier).
+func NewUserInfoModifier() Modifier {
+	return Modifier{
+		Name: UserInfoModifierName,
+		Pre: func(w http.ResponseWriter, r *http.Request) *http.Request {
+			if r.Method == http.MethodOptions {
+				return r
+			}
 
-					userInfo := r.Header.Get("X-Apigateway-Api-Userinfo")
+			claims, err := (APIGatewayAuthenticator{}).Authenticate(r)
+			if err != nil {
+				if !errors.Is(err, ErrNoCredentials) {
+					loggerFor(w).Warnf("%v", err)
+				}
+				return r
+			}
 
-					data, err := base64.RawURLEncoding.DecodeString(userInfo)
-					if err != nil {
-						logger.Warnf("invalid user info. base64 decoding failed: %s: %v", userInfo, err)
-						return r
-					}
+			if sub := claims.String("sub"); sub != "" {
+				r = withLoggerField(w, r, "user_id", sub)
+			}
+			return r.WithContext(withClaims(r.Context(), claims))
+		},
+	}
+}
 
-					var m map[string]any
-					if err := json.Unmarshal(data, &m); err != nil {
-						logger.Warnf("invalid user info. JSON unmarshaling failed: %s: %v", userInfo, err)
-						return r
-					}
+// APIGatewayUserInfoHeader is the header the GCP API Gateway uses to pass the
+// claims of the verified JWT to the backend.
+const APIGatewayUserInfoHeader = "X-Apigateway-Api-Userinfo"
 
-					return r.WithContext(WithUserID(r.Context(), fmt.Sprint(m["sub"])))
-				},
-			})
-		})
+// APIGatewayAuthenticator is an Authenticator that reads the claims from the
+// X-Apigateway-Api-Userinfo header. The GCP API Gateway has already verified
+// the JWT, therefore this must only be used behind the gateway.
+type APIGatewayAuthenticator struct{}
+
+var _ Authenticator = APIGatewayAuthenticator{}
+
+// Authenticate implements Authenticator.
+func (APIGatewayAuthenticator) Authenticate(r *http.Request) (Claims, error) {
+	userInfo := r.Header.Get(APIGatewayUserInfoHeader)
+	if userInfo == "" {
+		return nil, ErrNoCredentials
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(userInfo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user info. base64 decoding failed: %s: %v", userInfo, err)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("invalid user info. JSON unmarshaling failed: %s: %v", userInfo, err)
+	}
+	return claims, nil
 }
 
-// GetUserInfo returns the user info from the request context.
+// GetUserID returns the subject of the Principal from the request context.
+// It returns an empty string if the request is not authenticated.
 func GetUserID(ctx context.Context) string {
-	user, _ := ctx.Value(userInfoKey{}).(string)
-	return user
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return p.Subject
 }
 
-// WithUserID returns a new context with the user info set.
+// WithUserID returns a new context with a Principal that only has the given
+// subject.
 func WithUserID(ctx context.Context, userID string) context.Context {
-	return context.WithValue(ctx, userInfoKey{}, userID)
-}
-
-type userInfoKey struct {
+	return WithPrincipal(ctx, NewPrincipal(Claims{"sub": userID}))
 }
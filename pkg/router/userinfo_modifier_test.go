@@ -1,6 +1,7 @@
 package router_test
 
 import (
+	"context"
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
@@ -11,10 +12,20 @@ import (
 	"github.com/poy/go-router/pkg/router"
 )
 
+func init() {
+	// The PathPrefix keeps the Modifier from applying to the other tests'
+	// Routes.
+	injection.Register[injection.Group[router.Modifier]](
+		func(ctx context.Context) injection.Group[router.Modifier] {
+			m := router.NewUserInfoModifier()
+			m.PathPrefix = "/user-info/"
+			return injection.AddToGroup[router.Modifier](ctx, m)
+		})
+}
+
 func TestAddUserInfoModifier(t *testing.T) {
 	t.Parallel()
 
-	router.AddUserInfoModifier()
 	ctx := injectiontesting.WithTesting(t)
 	modifiers := injection.Resolve[injection.Group[router.Modifier]](ctx).Vals()
 	rec := httptest.NewRecorder()
@@ -38,7 +49,6 @@ func TestAddUserInfoModifier(t *testing.T) {
 func TestAddUserInfoModifier_options(t *testing.T) {
 	t.Parallel()
 
-	router.AddUserInfoModifier()
 	ctx := injectiontesting.WithTesting(t)
 	modifiers := injection.Resolve[injection.Group[router.Modifier]](ctx).Vals()
 	rec := httptest.NewRecorder()
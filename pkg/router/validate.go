@@ -0,0 +1,343 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldError describes why a single field failed validation.
+type FieldError struct {
+	// Field is the JSON path of the field (e.g., items[0].name).
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned by Validate (and therefore ReadRequest) when
+// a value does not satisfy the rules in its validate struct tags.
+type ValidationErrors []FieldError
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validate validates v against the validate struct tags of its fields. It
+// returns ValidationErrors listing every invalid field. The tag is a comma
+// separated list of rules:
+//
+//	required     the field must not be the zero value
+//	min=N        numbers must be >= N, strings, slices and maps must have a
+//	             length >= N
+//	max=N        like min, but an upper bound
+//	enum=a|b|c   the field must be one of the given values
+//	pattern=RE   strings must match the regular expression. As the pattern
+//	             may contain commas, it must be the last rule.
+//
+// For example:
+//
+//	type CreateWidget struct {
+//		Name string `json:"name" validate:"required,max=64,pattern=^[a-z-]+$"`
+//		Size string `json:"size" validate:"enum=small|large"`
+//	}
+//
+// The rules other than required aren't checked for fields that weren't set
+// (i.e., nil or, except for numbers, the zero value). Use a pointer for
+// optional numbers.
+func Validate(v any) error {
+	if v == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateRequestBody decodes the request body into a new value of the
+// schema's type and validates it. The body is replaced so that the handler
+// can still read it. It returns the status code to respond with if the body
+// is not valid.
+func validateRequestBody(req *http.Request, schema any) (int, error) {
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return http.StatusRequestEntityTooLarge, err
+			}
+			return http.StatusBadRequest, fmt.Errorf("failed to read request body: %v", err)
+		}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	v := reflect.New(reflect.TypeOf(schema))
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err)
+	}
+	if err := Validate(v.Interface()); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+	return 0, nil
+}
+
+func validateValue(v reflect.Value, path string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if !ok || (!f.IsExported() && !f.Anonymous) {
+				continue
+			}
+
+			fieldPath := joinFieldPath(path, name)
+			if f.Anonymous && f.Tag.Get("json") == "" {
+				// Embedded structs have their fields promoted.
+				fieldPath = path
+			}
+
+			fv := v.Field(i)
+			if f.IsExported() {
+				rules := parseValidateTag(f.Tag.Get("validate"))
+				if msg := rules.check(fv); msg != "" {
+					*errs = append(*errs, FieldError{Field: fieldPath, Message: msg})
+					continue
+				}
+			}
+			validateValue(fv, fieldPath, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs)
+		}
+	}
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// validationRules are the parsed rules from a validate struct tag.
+type validationRules struct {
+	required bool
+	min      *float64
+	max      *float64
+	enum     []string
+	pattern  string
+}
+
+func parseValidateTag(tag string) validationRules {
+	var rules validationRules
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "pattern=") {
+			// The pattern consumes the rest of the tag.
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			rules.required = true
+		case "min":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				rules.min = &f
+			}
+		case "max":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				rules.max = &f
+			}
+		case "enum":
+			rules.enum = strings.Split(value, "|")
+		case "pattern":
+			rules.pattern = value
+		}
+	}
+	return rules
+}
+
+// check returns a message describing why v doesn't satisfy the rules or an
+// empty string if it does.
+func (r validationRules) check(v reflect.Value) string {
+	if v.IsZero() && r.required {
+		return "is required"
+	}
+	if !isSet(v) {
+		// Optional fields that weren't set aren't checked any further.
+		return ""
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	var (
+		n       float64
+		measure = "must be"
+	)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n = float64(v.Len())
+		measure = "length must be"
+	default:
+		if r.min != nil || r.max != nil {
+			return fmt.Sprintf("min and max are not supported for %s", v.Kind())
+		}
+	}
+	if r.min != nil && n < *r.min {
+		return fmt.Sprintf("%s at least %v", measure, *r.min)
+	}
+	if r.max != nil && n > *r.max {
+		return fmt.Sprintf("%s at most %v", measure, *r.max)
+	}
+
+	if len(r.enum) > 0 {
+		s := fmt.Sprint(v.Interface())
+		found := false
+		for _, e := range r.enum {
+			if s == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("must be one of [%s]", strings.Join(r.enum, ", "))
+		}
+	}
+
+	if r.pattern != "" && v.Kind() == reflect.String {
+		re, err := compilePattern(r.pattern)
+		if err != nil {
+			return fmt.Sprintf("invalid pattern %q: %v", r.pattern, err)
+		}
+		if !re.MatchString(v.String()) {
+			return fmt.Sprintf("must match pattern %q", r.pattern)
+		}
+	}
+
+	return ""
+}
+
+// isSet returns false if an optional field wasn't set. Numbers are always
+// set as their zero value is a valid value that the rules have to be checked
+// against. Other fields aren't set if they are nil or, for those that can't
+// be nil (e.g., strings), their zero value.
+func isSet(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return !v.IsNil()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return !v.IsZero()
+	}
+}
+
+// apply adds the rules to the JSON Schema of the field.
+func (r validationRules) apply(s *jsonSchema, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s.MinLength, s.MaxLength = floatToInt(r.min), floatToInt(r.max)
+	case reflect.Slice, reflect.Array:
+		s.MinItems, s.MaxItems = floatToInt(r.min), floatToInt(r.max)
+	case reflect.Map:
+		s.MinProperties, s.MaxProperties = floatToInt(r.min), floatToInt(r.max)
+	default:
+		if r.min != nil {
+			s.Minimum = r.min
+		}
+		if r.max != nil {
+			s.Maximum = r.max
+		}
+	}
+
+	for _, e := range r.enum {
+		var value any = e
+		switch s.Type {
+		case "integer", "number":
+			if f, err := strconv.ParseFloat(e, 64); err == nil {
+				value = f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(e); err == nil {
+				value = b
+			}
+		}
+		s.Enum = append(s.Enum, value)
+	}
+
+	if r.pattern != "" {
+		s.Pattern = r.pattern
+	}
+}
+
+func floatToInt(f *float64) *int {
+	if f == nil {
+		return nil
+	}
+	i := int(*f)
+	return &i
+}
+
+var patternCache sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,183 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poy/go-dependency-injection/pkg/injection"
+	injectiontesting "github.com/poy/go-dependency-injection/pkg/injection/testing"
+	"github.com/poy/go-router/pkg/router"
+)
+
+type validatedItem struct {
+	Name string `json:"name" validate:"required,pattern=^[a-z]+$"`
+}
+
+type validatedRequest struct {
+	Name  string          `json:"name" validate:"required,min=2,max=5"`
+	Size  string          `json:"size,omitempty" validate:"enum=small|large"`
+	Count int             `json:"count" validate:"min=1,max=10"`
+	Items []validatedItem `json:"items" validate:"max=2"`
+}
+
+func init() {
+	injection.Register[injection.Group[router.Route]](func(ctx context.Context) injection.Group[router.Route] {
+		return injection.AddToGroup[router.Route](ctx, router.Route{
+			Path:          "/validated",
+			Method:        http.MethodPost,
+			RequestSchema: validatedRequest{},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The body should still be readable.
+				data, _ := io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				w.Write(data)
+			}),
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	err := router.Validate(validatedRequest{
+		Name:  "a",
+		Size:  "medium",
+		Count: 11,
+		Items: []validatedItem{{Name: "ok"}, {Name: "NOT-OK"}},
+	})
+
+	var errs router.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	expected := router.ValidationErrors{
+		{Field: "name", Message: "length must be at least 2"},
+		{Field: "size", Message: "must be one of [small, large]"},
+		{Field: "count", Message: "must be at most 10"},
+		{Field: "items[1].name", Message: `must match pattern "^[a-z]+$"`},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, errs)
+	}
+}
+
+func TestValidate_valid(t *testing.T) {
+	t.Parallel()
+
+	if err := router.Validate(&validatedRequest{Name: "abc", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_zero(t *testing.T) {
+	t.Parallel()
+
+	err := router.Validate(struct {
+		Count    int      `json:"count" validate:"min=1"`
+		Negative float64  `json:"negative" validate:"max=-1"`
+		Optional *int     `json:"optional" validate:"min=1"`
+		Tags     []string `json:"tags" validate:"min=1"`
+	}{})
+
+	var errs router.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	expected := router.ValidationErrors{
+		{Field: "count", Message: "must be at least 1"},
+		{Field: "negative", Message: "must be at most -1"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, errs)
+	}
+}
+
+func TestValidate_required(t *testing.T) {
+	t.Parallel()
+
+	err := router.Validate(validatedRequest{Count: 1})
+	if err == nil || err.Error() != "validation failed: name: is required" {
+		t.Fatalf("expected required error, got %v", err)
+	}
+}
+
+func TestReadRequest_validates(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "a", "count": 1}`))
+	if _, err := router.ReadRequest[validatedRequest](req); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestRoute_RequestSchema(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+		assert       func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "valid",
+			body:         `{"name": "abc", "count": 2}`,
+			expectedCode: http.StatusCreated,
+			assert: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				if actual, expected := rec.Body.String(), `{"name": "abc", "count": 2}`; actual != expected {
+					t.Fatalf("expected %s, got %s", expected, actual)
+				}
+			},
+		},
+		{
+			name:         "malformed",
+			body:         `{"name":`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid",
+			body:         `{"name": "abcdef", "count": 0}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			assert: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var body struct {
+					Fields []router.FieldError `json:"fields"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				expected := []router.FieldError{
+					{Field: "name", Message: "length must be at most 5"},
+					{Field: "count", Message: "must be at least 1"},
+				}
+				if !reflect.DeepEqual(body.Fields, expected) {
+					t.Fatalf("expected %+v, got %+v", expected, body.Fields)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		// Avoid issues with closure.
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := injectiontesting.WithTesting(t)
+			r := injection.Resolve[router.Router](ctx)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/validated", strings.NewReader(tc.body)))
+			expectedStatusCode(t, rec, tc.expectedCode)
+			if tc.assert != nil {
+				tc.assert(t, rec)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// marshalYAML encodes v as YAML. It does this by first encoding v as JSON,
+// so it honors the same struct tags as encoding/json. Strings are always
+// emitted as double quoted scalars, which YAML shares with JSON.
+func marshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeYAML(&buf, generic, 0)
+	return buf.Bytes(), nil
+}
+
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+func writeYAML(buf *bytes.Buffer, v any, indent int) {
+	writeYAMLWithPrefix(buf, v, indent, strings.Repeat("  ", indent))
+}
+
+// writeYAMLWithPrefix writes v at the given indentation, using first as the
+// prefix of the first line. This allows maps within lists to start on the
+// same line as the "-".
+func writeYAMLWithPrefix(buf *bytes.Buffer, v any, indent int, first string) {
+	prefix := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			buf.WriteString(first + "{}\n")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			p := prefix
+			if i == 0 {
+				p = first
+			}
+			buf.WriteString(p + yamlKey(k) + ":")
+			writeYAMLValue(buf, v[k], indent+1)
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(first + "[]\n")
+			return
+		}
+		for i, x := range v {
+			p := prefix
+			if i == 0 {
+				p = first
+			}
+			writeYAMLWithPrefix(buf, x, indent+1, p+"- ")
+		}
+	default:
+		buf.WriteString(first + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLValue writes a value that follows a "key:".
+func writeYAMLValue(buf *bytes.Buffer, v any, indent int) {
+	switch x := v.(type) {
+	case map[string]any:
+		if len(x) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+	case []any:
+		if len(x) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+	default:
+		buf.WriteString(" " + yamlScalar(v) + "\n")
+		return
+	}
+	buf.WriteString("\n")
+	writeYAML(buf, v, indent)
+}
+
+func yamlKey(k string) string {
+	switch strings.ToLower(k) {
+	case "true", "false", "null", "yes", "no", "on", "off", "y", "n":
+		// These would be read back as something other than a string.
+	default:
+		if plainYAMLKey.MatchString(k) {
+			return k
+		}
+	}
+	return yamlScalar(k)
+}
+
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}